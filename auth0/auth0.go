@@ -1,14 +1,18 @@
 package auth0
 
 import (
+	"time"
+
 	"github.com/danielgtaylor/openapi-cli-generator/cli"
 	"github.com/danielgtaylor/openapi-cli-generator/oauth"
 )
 
 type config struct {
-	typeName string
-	extra    []string
-	scopes   []string
+	typeName     string
+	extra        []string
+	scopes       []string
+	redirectPort int
+	loginTimeout time.Duration
 }
 
 // Extra provides the names of additional parameters to use to store information
@@ -37,6 +41,24 @@ func Type(name string) func(*config) error {
 	}
 }
 
+// RedirectPort sets the loopback port to receive the authorization code login
+// redirect on. Use `oauth.RandomPort` to pick any free port.
+func RedirectPort(port int) func(*config) error {
+	return func(c *config) error {
+		c.redirectPort = port
+		return nil
+	}
+}
+
+// LoginTimeout sets how long to wait for the user to log in via the
+// authorization code flow.
+func LoginTimeout(timeout time.Duration) func(*config) error {
+	return func(c *config) error {
+		c.loginTimeout = timeout
+		return nil
+	}
+}
+
 // InitClientCredentials sets up the Auth0 client credentials flow. Must be
 // called *after* you have called `cli.Init()`. Pass in profile-related extra
 // variables to store them alongside the default profile information.
@@ -49,7 +71,9 @@ func InitClientCredentials(issuer string, options ...func(*config) error) {
 		}
 	}
 
-	handler := oauth.NewClientCredentialsHandler(issuer+"oauth/token", append([]string{"audience"}, c.extra...), []string{"audience"}, c.scopes)
+	// The token URL is discovered from the issuer.
+	handler := oauth.NewClientCredentialsHandler("", append([]string{"audience"}, c.extra...), []string{"audience"}, c.scopes)
+	handler.Issuer = issuer
 
 	cli.UseAuth(c.typeName, handler)
 }
@@ -68,10 +92,33 @@ func InitAuthCode(clientID string, issuer string, options ...func(*config) error
 
 	cli.UseAuth(c.typeName, &oauth.AuthCodeHandler{
 		ClientID:     clientID,
-		AuthorizeURL: issuer + "authorize",
-		TokenURL:     issuer + "oauth/token",
+		Issuer:       issuer,
 		Keys:         append([]string{"audience"}, c.extra...),
 		Params:       []string{"audience"},
 		Scopes:       c.scopes,
+		RedirectPort: c.redirectPort,
+		LoginTimeout: c.loginTimeout,
+	})
+}
+
+// InitDeviceCode sets up the Auth0 device authorization flow, which lets users
+// log in from a browser on another device. Must be called *after* you have
+// called `cli.Init()`. Pass in profile-related extra variables to store them
+// alongside the default profile information.
+func InitDeviceCode(clientID string, issuer string, options ...func(*config) error) {
+	var c config
+
+	for _, option := range options {
+		if err := option(&c); err != nil {
+			panic(err)
+		}
+	}
+
+	cli.UseAuth(c.typeName, &oauth.DeviceCodeHandler{
+		ClientID: clientID,
+		Issuer:   issuer,
+		Keys:     append([]string{"audience"}, c.extra...),
+		Params:   []string{"audience"},
+		Scopes:   c.scopes,
 	})
 }
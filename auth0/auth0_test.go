@@ -0,0 +1,60 @@
+package auth0
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/openapi-cli-generator/cli"
+	"github.com/danielgtaylor/openapi-cli-generator/cli/clitest"
+	"github.com/danielgtaylor/openapi-cli-generator/oauth"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientCredentialsDiscovery(t *testing.T) {
+	clitest.Setup(t)
+	viper.Set("profile", "default")
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			w.Write([]byte(`{"issuer": "` + server.URL + `/", "token_endpoint": "` + server.URL + `/custom/token"}`))
+		case "/custom/token":
+			r.ParseForm()
+			assert.Equal(t, "api", r.PostForm.Get("audience"))
+			w.Write([]byte(`{"access_token": "abc", "token_type": "bearer", "expires_in": 3600}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	InitClientCredentials(server.URL + "/")
+	cli.Creds.Set("profiles.default.client_id", "id1")
+	cli.Creds.Set("profiles.default.client_secret", "secret1")
+	cli.Creds.Set("profiles.default.audience", "api")
+
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com/", nil)
+	log := zerolog.Nop()
+	assert.NoError(t, cli.AuthHandlers[""].OnRequest(&log, req))
+	assert.Equal(t, "Bearer abc", req.Header.Get("Authorization"))
+}
+
+func TestAuthCodeOptions(t *testing.T) {
+	clitest.Setup(t)
+
+	InitAuthCode("id1", "https://example.com/",
+		Type("user"),
+		RedirectPort(9000),
+		LoginTimeout(time.Minute))
+
+	handler := cli.AuthHandlers["user"].(*oauth.AuthCodeHandler)
+	assert.Equal(t, 9000, handler.RedirectPort)
+	assert.Equal(t, time.Minute, handler.LoginTimeout)
+}
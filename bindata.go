@@ -80,99 +80,118 @@ func (fi bindataFileInfo) Sys() interface{} {
 }
 
 var _bindataTemplatesCommandstmpl = []byte(
-	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd4\x59\xdd\x73\xdb\xb8\x11\x7f\x26\xff\x0a\x1c\x27\xb9\x21\x13\x99\xca" +
-	"\x5d\x6f\xfa\xa0\x9e\x3a\x93\x38\x9f\x33\x17\xc7\xb5\x9d\xbb\x07\xd7\xd3\xc0\xe4\x4a\xc2\x18\x04\x68\x10\xf2\x47" +
-	"\x75\xfc\xdf\x3b\x0b\x80\x24\x28\x52\xb2\x9d\xf6\xa5\x2f\x36\x4d\x80\xd8\xaf\xdf\xfe\x76\x17\x9e\x4e\xc9\xa1\xcc" +
-	"\x81\x2c\x41\x80\xa2\x1a\x72\x72\x79\x4f\x64\x09\x82\x96\xec\x20\xe3\xec\xc0\x2d\x48\x95\x92\xb7\x5f\xc8\xd1\x97" +
-	"\x33\xf2\xee\xed\xa7\xb3\x34\x9c\x4e\xc9\x29\x00\x59\x69\x5d\x56\xb3\xe9\x74\xc9\xf4\x6a\x7d\x99\x66\xb2\x98\xe6" +
-	"\x54\x30\xe0\x4b\x4d\xef\xb9\x54\xd3\xd1\xb3\xc2\xb0\xa4\xd9\x15\x5d\x02\x29\x28\x13\x61\xc8\x8a\x52\x2a\x4d\xe2" +
-	"\x30\xd8\x6c\x08\x5b\x90\xf4\x93\x79\x51\xa5\xef\x0b\x4d\xea\x3a\x5a\x14\x3a\xda\x6c\x08\x88\x9c\xd4\xf5\x60\xd3" +
-	"\xa9\x56\x4c\x2c\x2b\xdc\x58\xd9\xc7\x3d\x9b\xcf\x58\x01\xb8\x53\xb3\x02\xbc\x6d\x61\x10\x3d\xcd\x84\x69\xc6\x59" +
-	"\xd4\xff\xaa\xbc\x5a\x4e\x41\x29\xa9\xaa\xad\x05\x55\x4d\xff\x0d\x4a\x72\xb9\x9c\x72\xb9\xdc\x5a\xac\xca\xc5\x4f" +
-	"\x7f\x99\x66\xf2\x52\xd1\xd1\x95\x1b\x56\x82\x32\x2b\xb2\xbc\x5a\xa6\x4c\x4c\x57\x3f\x0b\x29\xa6\x4b\x10\x9a\x43" +
-	"\x41\x45\x7a\xf3\x73\x14\x26\x61\xb8\xd9\x90\x1c\x16\x4c\x00\x89\x4a\xaa\x68\x51\x45\xce\xfe\x03\xa2\xa8\x58\x02" +
-	"\x49\xbf\x94\x9a\x49\x41\xf9\xb1\x59\x36\xab\x66\x99\x2d\x08\x5c\x93\xf4\xec\xbe\x04\x12\x5d\x4a\xc9\x81\x0a\xfb" +
-	"\x71\x10\x64\x45\x9e\xbe\xe7\x74\x59\xc5\x49\xfa\x46\x4a\x1e\xa3\xd3\xd2\xc3\xdf\x3e\x1d\x51\xeb\xc8\x09\x59\x50" +
-	"\x5e\xc1\x84\x98\x85\xb7\x50\x65\x8a\x19\x39\xb8\x98\x38\x09\xc0\x2b\xe8\x8b\x61\x42\xff\xf5\x97\x31\x21\x9f\x70" +
-	"\x61\x44\xca\xab\xa7\x4a\x58\x70\x49\x77\xc8\x78\x6f\x97\xc6\xa4\xa4\x8f\x91\x33\x3c\xd1\x62\x70\xe4\xc0\x28\x7a" +
-	"\xe0\xbc\x16\xa6\x07\x1d\x16\xbd\x98\xfd\x41\x99\x06\xe5\x82\x35\x0c\xc6\x2d\x65\xfa\x00\x8f\xb7\xfb\x76\x07\xc6" +
-	"\xad\x9f\xae\x30\xd1\xac\xfc\x9e\xc8\x8c\xb3\xf4\x14\xf4\xe1\xba\xd2\xb2\xb0\x32\xb2\x22\x4f\xc2\x30\x60\x0b\xe2" +
-	"\xcb\xfd\x48\x2b\xf7\x48\x36\x61\x10\x58\xa8\xa5\x6f\x98\xc8\x8f\xdb\xcf\x9a\xcd\x49\x18\xd4\xa1\x97\x64\x9b\x0d" +
-	"\x79\x26\x50\xbd\xd9\x9c\xa4\x4e\x4f\xf3\x92\x96\xcc\xbc\xfb\x20\xb7\xde\x1e\xaf\x2f\x39\xcb\xcc\x9a\x7d\xec\x76" +
-	"\x84\x37\x54\x91\xe6\xe3\xba\x3e\x5d\x5f\x66\xb2\x28\xa8\xc8\x09\x42\x38\x0c\x17\x6b\x91\xf9\xeb\xa0\x6e\x40\xa1" +
-	"\xda\xe7\x17\x05\x2d\xcf\x2d\x55\x5c\xd8\x5f\x68\x8a\x02\xbd\x56\x62\x6c\x75\x63\x62\xe5\x22\xf2\xac\x32\x07\x19" +
-	"\x95\xdc\x99\x0e\x0f\xa3\xdf\x05\x41\x94\x77\x91\x8f\x66\x26\x1a\xee\x8c\x6d\x4c\x4c\xec\xfe\xb5\xe2\x5b\xfb\xbe" +
-	"\x9e\xfc\xd6\xae\xd7\x13\xab\x4d\x03\x9c\x3a\xb4\x8e\x75\xda\xc9\x12\xf9\x09\x0f\x44\x05\xbf\x34\x7f\x59\x1d\xa7" +
-	"\x53\xd2\xf7\x6b\x5d\x23\x36\x5a\x9f\xe2\x6a\x0b\x91\x30\xf0\x3d\x38\xfe\x41\xdc\x0a\x4e\x4f\xe0\x7a\xcd\x14\xe4" +
-	"\x2d\xb9\xf4\x4f\xb6\x1e\x99\x90\x56\x73\x0b\x1c\xf2\xc2\xf0\x5b\xfa\x3b\xfe\x74\x54\x7d\x48\xc5\x47\x7a\x03\x6f" +
-	"\x64\x7e\x4f\xea\x7a\x42\x2e\xf1\xc1\x79\xb4\xf9\x3a\x21\xf1\x8b\x8e\x01\x4f\xa0\x2a\xa5\x40\xac\xa3\xd0\x13\x13" +
-	"\x48\x43\x02\xf8\xb9\xa1\x64\x0b\xd6\x15\x15\x39\x07\x75\x4c\xf5\x0a\xdd\x63\x12\xe3\xa3\x7d\xd7\x64\x4d\x18\x20" +
-	"\xe0\x47\x61\x65\xc2\xe9\x1f\x61\x4f\xb0\x88\xae\x6b\x12\x91\x97\xc4\x5b\x0e\x83\x00\x13\x2b\xe8\xf0\x62\x4d\xfd" +
-	"\x00\xba\x61\x0b\xbb\x64\xb8\x80\x2d\x88\xdb\x38\x9f\x93\x28\xb2\xc2\x9a\x37\x63\x30\x3e\x6f\x4f\xfb\x24\x74\x73" +
-	"\xd4\x01\x13\x39\xdc\x45\xc9\xc5\xb9\x41\xd1\x45\xa3\xc3\x5a\x71\x54\xc0\x6e\x7a\x69\xec\x36\x16\xa0\xc1\x8e\x88" +
-	"\x1c\x7e\xd8\x84\x3c\x33\xa1\x31\xf8\x19\x04\x15\xb5\xea\x4a\x86\xdd\x99\x7e\x12\x58\x72\xf4\xaa\xa1\x5a\x23\x6e" +
-	"\xee\x42\x56\xa5\x27\x50\x72\x9a\x41\xbc\x56\xdc\x90\xd1\xb7\xcd\x37\x83\x0e\xf7\xb5\xf3\xfc\x66\xf3\xad\xfe\x66" +
-	"\x68\xab\x5b\x6a\xe1\x33\x21\x3f\x25\x8d\xe8\x06\xf8\x7d\xfe\x0a\x14\x5c\xa3\xca\xc8\x63\x87\x9c\x81\xd0\x29\x5a" +
-	"\xf9\x19\xf4\x4a\xe2\x96\x38\xc1\x2c\x42\x1d\x92\xb0\x97\xce\x8f\x32\x78\x68\xef\xf5\x1a\xd4\x7d\x6b\x30\x0a\x9f" +
-	"\x13\x05\xd7\xe9\xeb\x3c\xff\x07\x2e\xd9\x52\xd0\x71\xf1\x88\x51\xce\x22\xbf\x74\x79\x02\x56\x40\x73\x50\xe3\x12" +
-	"\x3e\x9a\xb5\xa7\x88\xe8\x7c\xe6\xb9\xec\x81\xd6\x20\xe8\x67\xf0\x6c\x4e\x1c\xd9\x7f\x00\x8d\x4b\x26\xc3\xfe\x24" +
-	"\x9a\x69\x6e\xb8\x60\xbb\xf8\x19\xe1\x36\x99\xbc\x63\x7e\x30\x68\x36\x1f\x1f\x31\x6e\x48\xc7\x18\xe8\x35\x22\x43" +
-	"\xff\x3e\xc2\xc1\x8b\x42\xa7\xa7\xa5\x62\x42\x2f\xe2\xe8\xf9\x8d\xf5\x87\xe7\x89\xa4\x95\xe2\xb7\x0a\x23\x9e\x7e" +
-	"\x8c\xab\x9f\x20\xac\xf1\x7c\x30\x84\xec\x28\xdf\x39\xa7\x19\xd2\xfb\xa1\xa3\x82\x71\xad\x0e\xa5\xd0\x20\xf4\x01" +
-	"\x7a\xb3\x69\x34\x3e\x43\xce\xa8\x63\xbf\x08\xfb\x84\xfc\xde\xf1\x0d\x9e\x99\x74\xaa\x78\x9a\x60\xd6\x58\x22\x7c" +
-	"\x03\x0b\xa9\x20\xf6\x78\x6c\xe2\xc2\x3e\x41\xe1\x89\x4d\xb5\xaa\x34\xbc\x8a\x98\x40\x8d\xde\xca\xd8\x71\x18\xbe" +
-	"\xfc\x61\x4e\x04\xe3\x56\x6d\x57\x54\x05\xe3\x13\xfb\xc3\x36\xc8\xe9\x1f\x8a\x96\x31\x28\x35\x21\x11\xa6\x1c\x54" +
-	"\x9a\x2c\x28\xe3\x90\x1b\xd4\x18\x9d\xb0\xbc\xe7\x90\xc9\x1c\xf2\x21\xad\x87\x56\x1c\x6a\x92\x9e\x6a\xaa\xd7\x95" +
-	"\x19\x63\x7e\x25\xbf\xbc\x7a\x65\x25\x3b\x65\x1c\x25\x7c\x15\x05\x55\xd5\x8a\xf2\xa6\x54\xc4\xd6\x88\x1f\x9d\x84" +
-	"\xe4\x6f\x03\xd5\x1f\xa3\x7b\x7b\x2c\xc7\x26\x42\xb9\xb3\x7d\x53\x8c\xaf\x6b\x8b\xb9\xbd\x1e\x79\x87\xbf\x16\x71" +
-	"\xf4\xf1\xec\xec\x98\x3c\xcf\x67\xe4\x79\x15\x4d\xb6\x0d\x6c\x5f\x98\x78\x26\xad\xaf\xe8\x42\x43\x6b\xab\x0d\xe4" +
-	"\x6b\x7c\xb5\x2b\x8e\x68\x7a\x63\xb9\xf5\xa4\x3d\xc1\xb7\xbf\xf1\xfd\xdc\xae\x59\xb0\x0a\xe8\x05\x02\xdb\x78\x50" +
-	"\x0b\x9a\xc1\xa6\xc6\x04\x4a\xe3\x41\xa4\x12\x9f\x7e\x1c\x53\x1b\x0f\xf4\xb4\x30\xbe\x18\x76\x8b\x8e\xa3\x6f\x4d" +
-	"\xf3\x6a\x08\xda\xef\x87\x9f\xd4\x9f\xb4\xad\xd0\xff\xa2\x53\x49\x6c\xd4\x8c\xa3\xa8\xd6\x50\x94\x1a\xb5\x7b\x15" +
-	"\x06\xc1\x42\x2a\xd2\xbc\xfa\xd5\x68\x67\xb5\x4f\x5f\xdb\x97\x55\x4b\x79\x6e\xd7\xcb\x97\xa1\xc5\x45\xcf\x1d\x0e" +
-	"\xbb\x63\xd6\x75\x96\xfc\x37\x76\x0e\x0c\x4c\xbc\xac\x19\xcb\x83\x21\xfa\x0f\xe5\x9a\xe7\x44\x48\x4d\x32\xca\x39" +
-	"\x71\x51\x6a\x3b\xd0\x06\xff\xf8\x13\x93\x99\x66\x7a\x4d\x39\xf1\x20\xd3\xac\x14\x54\x67\x2b\xdb\xb6\x07\x7e\x6d" +
-	"\x36\xef\x5d\xe0\x3f\xdb\xe7\xa6\x36\x05\xf6\x34\xeb\x28\x8b\xfb\x0f\xa0\xcd\xa6\xdf\x29\x5f\xdb\xfc\x4e\x0d\x3f" +
-	"\xde\x69\xc7\x8c\xa7\xc0\x21\xd3\x96\xc1\x5d\x29\x7b\xcd\xf9\x29\x68\x8d\x7d\x4a\x9c\xf4\x72\x62\xdc\x17\x8f\x71" +
-	"\xc6\x12\x34\x69\x34\xbf\x41\x5d\xac\x23\x9c\x27\x02\xb3\xe4\xeb\x6d\x94\xb6\x15\xe6\x0c\x89\xd0\xe8\x77\x7e\x71" +
-	"\x79\xaf\xc1\xa4\xd3\xbb\xbb\x12\x32\x0d\x39\xf9\x93\xd8\x92\x43\xa2\xe7\xd7\x98\x6d\xc9\xc4\xf9\xf4\x7b\xf4\xfd" +
-	"\xc3\x69\x68\x7d\x8f\x8c\xb5\x56\xad\xa6\x6d\x8d\xb4\xab\xee\xac\xb6\x0f\x32\x8c\x84\x23\xb6\xfb\xaa\x2d\x9d\x5b" +
-	"\xe2\x1a\x4e\xb3\x49\x4b\x32\x2a\xd0\x3f\x0a\x68\xb6\x22\x39\x54\x08\x4e\x52\x99\xa3\x2e\x21\xa3\xeb\x0a\xc8\xf3" +
-	"\x8a\xb0\xca\x52\xdf\x20\x64\xfb\x7d\xd1\xaa\xe8\x4d\xe8\x41\x10\x5c\x2a\xa0\x57\xdd\x5a\x5b\x8d\x83\xba\xdf\x1a" +
-	"\xe1\x5f\x9a\x15\x90\x9e\x72\x80\x32\xb6\x13\x3b\xa7\x58\x91\x5f\xd8\xf7\x90\x49\x91\xb7\x8c\x8b\x94\xe9\xb2\xfc" +
-	"\xef\xf3\xbd\x69\xde\x77\xc9\x11\xdc\xc6\xd1\x67\x7a\xc7\x8a\x75\xd1\x9c\x50\x11\xb8\xcb\x00\x72\xbf\xfa\x75\x65" +
-	"\x62\x8b\x15\xb7\xc6\xda\x13\x58\xb2\x0a\x99\xbe\xea\xcf\xbf\x66\xe0\x51\x52\xea\xa6\x2a\x9c\x48\xa9\xed\x34\x5f" +
-	"\xf5\x47\x1a\xb3\x69\x4e\x7e\x34\x57\x50\xe9\xa1\x5d\x31\x9a\x7f\xad\x60\xd6\x1b\x71\xec\x04\x6a\x06\x44\xbb\x90" +
-	"\x9e\xb9\xe6\xcf\xae\xfc\x26\xc5\x72\xe6\x30\xad\xae\x72\x79\x2b\xe2\xd1\xbb\x8f\x49\xd8\xf6\x20\xc3\x31\x6b\x4e" +
-	"\xb4\x5a\x43\xe8\x17\xcd\x46\x7f\x37\x9b\xce\xb7\x64\xfb\x3b\x50\x85\x36\xaf\xf6\xe9\x10\x06\xf6\xa6\xc5\xb4\x60" +
-	"\xbd\x5b\x16\x0c\x24\x7a\x6d\xa7\x47\x70\x43\xdf\x15\xf8\x3d\x31\xcc\x4f\x32\x50\x9a\x32\x41\xe0\x06\x84\x26\x52" +
-	"\xb5\x00\xc7\xbe\x8a\xd8\xb0\x32\xb1\xf4\x1d\x16\xbd\xe1\x32\xbb\x42\x14\x40\xb6\x36\x0a\xa2\x1f\xd6\x15\x54\xa4" +
-	"\x94\xb6\xb5\xd0\x92\x94\xa0\x98\xcc\x19\x52\xed\x3d\xc9\x56\x90\x5d\x7d\x87\xc4\xda\x05\x1c\x9b\x48\x67\x58\x8c" +
-	"\xe6\x6c\x0d\x45\x3b\x0a\x6e\x60\x4b\xae\xbb\xfa\x69\x2e\x7f\xba\xd1\x16\xc1\x6d\x13\x31\x2b\xf2\x1d\x2e\xf4\x60" +
-	"\x95\x7e\xad\x3a\xec\xb4\x13\x40\xfa\x9a\x33\x8a\xb6\xb7\x39\xec\x5e\xcc\xc8\x79\xef\x62\x25\xe8\x4d\x30\x83\xaf" +
-	"\x82\xc0\xc8\xf0\x04\x6c\x77\xe4\xcd\x6d\xca\xc8\x82\x8f\xf1\xf6\xce\xcc\xed\xdd\x85\x72\x03\xbd\x06\xde\xa8\xb6" +
-	"\x5a\x56\x33\x62\x3d\xf0\x99\x09\xcc\xf8\x23\x7c\x87\xe4\xc2\x41\xec\x2d\xd5\xcd\x19\x27\x6b\x31\x23\xe8\xf4\x18" +
-	"\x3d\xfa\xa2\xe7\xce\x09\xa1\x6a\x59\xb5\x4e\x69\x82\xe2\xb7\xbe\x8f\x6c\x8e\x9e\xdd\xf5\x86\xe0\x3d\x7a\xa1\xc4" +
-	"\x73\x3c\xf5\x8e\xd4\xf5\xc5\xb0\x87\x18\x69\xa3\x83\x20\xe0\x72\x99\xbe\xa7\x9a\xf2\x38\xc1\x9a\x80\x15\x28\x49" +
-	"\x3f\x57\xcb\x38\x32\x15\xc2\x74\x0e\x88\xd0\xa4\x89\x4a\xe8\x07\xc7\xfe\x85\x7b\x7c\xd4\xba\xdb\x49\x4b\xe2\x48" +
-	"\xa3\xdc\x94\xa4\xe6\xea\xbb\x33\xa2\x19\xbf\xe2\xa4\x7f\x71\xe6\x73\xff\x23\xef\xcf\xfa\xf0\x1f\x47\x7f\xd3\xd3" +
-	"\xc0\x1d\x2d\x4a\x0e\x95\xeb\x27\xc3\x7e\x67\x03\x77\xe6\xfc\x77\xcd\x26\x87\xbb\xf6\xa3\x97\x73\x12\x11\x73\xa3" +
-	"\xd4\x32\x9b\x33\x1c\x5b\xf9\x38\x21\x2f\x49\x64\xa2\xdb\xea\xeb\x92\xc9\xbc\x04\x8c\xce\x3f\x45\x34\x2c\x71\x7b" +
-	"\xf2\x72\x47\x5a\xee\xca\xca\x9d\x49\xb9\x37\x27\x07\x29\xb9\x9d\x78\xf5\x64\x64\x74\xde\x97\x8e\x8f\xcc\xc6\xc6" +
-	"\x8c\x8f\x2c\xcf\x41\xb4\xe2\xec\x9f\x33\xd3\x5b\xb4\x4b\xa3\x2a\xb8\x50\xcd\xda\xc0\xda\x5d\x0f\x26\xf9\xae\xd4" +
-	"\xfe\x9e\xcc\x6e\x8c\x18\xde\x18\x04\x01\x0e\xf7\x13\x7f\xe4\xfd\x00\x1a\x37\xc4\xc3\x4b\x01\x7b\xfc\xf9\xb8\x86" +
-	"\x75\x3d\xbb\x70\x79\x38\xda\x50\xee\x49\xe6\xaf\x82\x5e\x72\xc0\x5a\x85\x2d\x30\x2a\xd4\x64\x74\x3d\x60\x59\xbf" +
-	"\xa3\x4c\x8f\x00\xf2\xaa\x99\xcc\x49\x5d\x63\xef\xde\x75\x72\xff\x6a\x21\xfc\xb8\xf9\xe8\x61\x82\x7b\x2a\xad\xed" +
-	"\xb9\x98\xee\x6e\xa4\xbf\xcf\x67\x96\x00\x33\x77\x83\xb0\x35\x3b\xb5\x33\xc3\xd6\x6d\xc6\x7b\xa9\x0a\xec\x1d\x95" +
-	"\x7b\x8a\xf7\xdc\x62\xec\x13\xee\xce\x41\xc9\xfe\x95\x45\x27\x76\xac\x3d\xb2\x6d\xfe\xf5\x71\xcb\x7e\x63\xa3\x54" +
-	"\xd8\xa6\xf7\xe8\x3d\xab\x47\x5b\xa3\x37\xae\x3d\x19\xe7\xd1\xe0\xb2\x38\xba\x20\xf3\x16\xc4\xcf\xb0\x81\xbc\xe8" +
-	"\x04\xf6\x10\xb6\x67\xf2\x1c\xc5\xe3\xf8\x3f\xde\x5c\x08\xba\xfb\xcf\x07\xff\xfb\xd6\x55\xe3\xe0\x76\x77\xa1\xe8" +
-	"\xcb\x34\x28\xad\xcc\xc4\x23\x6d\xf3\x85\x4c\xe2\x77\x34\x4f\x1f\x7a\xbb\xf3\x4c\xe2\xb7\x4e\xdd\x9a\x75\x77\x43" +
-	"\x77\x2f\x80\x1e\x1e\x7a\x7d\x34\xa1\x01\x6a\xd9\xa4\x19\x1a\x38\xbc\x47\xf5\x07\xda\xb1\x08\xf5\xff\x59\x91\x6f" +
-	"\x3b\x6c\x80\xa2\xff\x7f\x97\x39\xf8\xa4\xa7\xa0\x0d\x97\x3f\x63\xb9\x23\xf1\x07\x5d\xb5\xbf\x11\x74\xa0\xdd\x45" +
-	"\x97\x63\x40\xf4\xc2\xe7\x91\xe4\xed\xde\xe6\x6f\x8f\x2f\xdc\xbd\x80\x19\x8d\x3b\xe3\xeb\x7e\x17\xb8\x55\x87\x6d" +
-	"\x75\x36\xcf\xdb\x83\x4c\xdb\x12\x8e\x77\x84\xee\x0a\x32\x4e\xfc\xde\xaf\x0e\xff\x13\x00\x00\xff\xff\x5d\xb1\xa7" +
-	"\x91\x03\x23\x00\x00")
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xd5\x1a\x6d\x6f\xd3\x48\xfa\x73\xfc\x2b\x66\x2d\xd8\x73\x20\x75\xd8\xbd" +
+	"\xd5\x7d\xe8\xd1\x93\xa0\xc0\x52\x89\x42\x8f\x16\xf8\xd0\xab\xc4\xd4\x9e\xa4\x16\x8e\x1d\xec\x71\x5f\xb6\x9b\xff" +
+	"\x7e\xcf\xcb\xcc\x78\x1c\x3b\x69\xcb\x9d\x74\xba\x4a\x24\xf1\x3c\x33\xf3\xbc\xbf\x9a\xe9\x54\xec\x97\xa9\x12\x73" +
+	"\x55\xa8\x4a\x6a\x95\x8a\xf3\x1b\x51\x2e\x55\x21\x97\xd9\x4e\x92\x67\x3b\x06\x50\x56\xb1\x78\xf5\x41\xbc\xff\x70" +
+	"\x22\x5e\xbf\x3a\x38\x89\x83\xe9\x54\x1c\x2b\x25\x2e\xb4\x5e\xd6\xbb\xd3\xe9\x3c\xd3\x17\xcd\x79\x9c\x94\x8b\x69" +
+	"\x2a\x8b\x4c\xe5\x73\x2d\x6f\xf2\xb2\x9a\x0e\xde\x15\x04\x4b\x99\x7c\x93\x73\x25\x16\x32\x2b\x82\x20\x5b\x2c\xcb" +
+	"\x4a\x8b\x28\x18\x85\x49\x59\x68\x75\xad\xc3\x60\x74\x7b\x2b\xb2\x99\x88\x0f\x08\x56\xc7\x6f\x16\x5a\xac\x56\xe1" +
+	"\x6c\xa1\x43\x80\xa8\x22\x85\xa7\xde\xa6\x63\x5d\x65\xc5\xbc\xc6\x8d\x35\xff\xdc\xb2\xf9\x24\x5b\x28\xdc\xa9\xe1" +
+	"\xdb\xdb\x06\x44\x3c\x8c\x9b\x29\x3c\xf5\xe9\x7d\x7b\x72\x72\xf4\xa2\xd1\x17\x88\xe1\x81\xf7\xa1\x50\x25\x1c\xf5" +
+	"\x69\xf7\xef\x58\x7e\x9b\x4f\x55\x55\x95\x55\x1d\x76\x01\x55\x3d\xfd\x43\x55\x65\x5e\xce\xa7\xf0\x6f\x0d\x58\x2f" +
+	"\x67\xbf\xfc\x75\x9a\x94\xe7\x95\x1c\x84\x5c\x66\x4b\x55\x11\xa4\x04\x04\x71\x56\x4c\x2f\x7e\x2d\xca\x62\x0a\x74" +
+	"\xe9\x5c\x2d\x64\x11\x5f\xfe\x1a\x06\xe3\x20\x00\xaa\x52\x35\xcb\x0a\x25\xc2\xa5\xac\xe4\xa2\x0e\x8d\x74\x77\x44" +
+	"\x25\x0b\x50\x6a\xfc\x61\xa9\xb3\xb2\x90\xf9\x11\x81\x09\x4a\x60\x90\x8f\xfa\x2e\xe2\x93\x9b\x25\x9c\x3d\x2f\xcb" +
+	"\x5c\xc9\x82\x0f\x8f\x46\xc9\x22\x8d\xdf\xe4\x72\x5e\x47\xe3\xf8\x25\x80\x22\xe4\x3e\xde\x7f\x77\xf0\x5e\xb2\x9a" +
+	"\x26\x62\x26\xf3\x5a\x4d\x04\x01\x5e\xa9\x3a\xa9\x32\xc2\x83\xc0\xb1\xc1\xa0\x60\x47\x17\x4d\x56\xe8\xbf\xfd\x36" +
+	"\x84\xe4\x00\x01\x03\x58\x9e\x3d\x14\xc3\x2c\x2f\xe5\x06\x1c\x6f\x18\x34\x84\x25\xbe\x0f\x9e\xfe\x8d\x6c\xe1\x03" +
+	"\x17\x86\xe1\x1d\xf7\x39\x27\xd8\x69\x2d\xdd\xd3\xd9\x17\x99\x69\x55\x19\x65\xf5\x95\x71\x05\xe0\x1d\xbc\x9e\xf7" +
+	"\x6d\x56\x8c\x81\x1f\x5f\xa0\x47\x33\xfe\x0e\x4a\x30\xf5\xf8\x58\xe9\xfd\xa6\xd6\xe5\x82\x71\x00\x36\x30\xab\x11" +
+	"\x08\xd5\xc7\xfb\x56\xd6\xe6\xa7\xb8\x05\x92\xd8\xd4\xe2\x97\x59\x91\x1e\xb9\x63\x76\x33\x20\x59\x05\x9e\x0b\xc3" +
+	"\xcf\x47\x05\x92\xb7\xbb\x27\x62\x43\x27\x2d\x82\xb3\xd1\xda\xef\xe5\xda\xea\x51\x73\x9e\x67\x09\xc1\xf8\x67\xbb" +
+	"\x23\xb8\x94\x95\xb0\x87\x57\xab\xe3\xe6\x1c\x9c\x06\xfc\x01\x62\x25\x88\x26\x08\x66\x4d\x91\xf8\x70\x55\x5d\x82" +
+	"\x20\x81\xec\xd3\xb3\x85\x5c\x9e\x72\x20\x3a\xe3\x2f\x64\xa5\x52\xba\xa9\x8a\x21\xe8\x2d\xe9\xca\x68\xe4\x51\x4d" +
+	"\x17\x11\x49\xe6\x4e\x63\x0f\x83\xe7\x46\xa3\x30\x6d\x35\x1f\xee\x92\x36\xcc\x1d\xeb\x36\x31\xe1\xfd\x4d\x95\xaf" +
+	"\xed\xfb\xf4\xf1\x9d\x83\xaf\x26\x4c\x8d\x35\x9c\x55\xb0\xda\xc0\xeb\x67\x59\x65\xf2\x3c\x57\xff\x03\x9e\xdb\x93" +
+	"\xa8\xee\x89\x78\x74\x29\xf3\x86\xd4\x6e\x59\x72\xc4\x99\x8b\x80\x6f\x67\x1d\xc0\xaa\xe1\x9f\x8f\x39\xd1\x78\x6c" +
+	"\x6f\x10\x44\x8b\x18\x62\x38\x84\x6d\x94\x2c\x52\xfd\xc1\x3e\x31\x3e\x48\x93\x5d\x03\x5b\xad\xd0\x49\x9c\x71\x21" +
+	"\xd4\xf9\x4a\x30\xf2\xc5\x3b\x7c\x20\x4a\xf4\xb5\x30\x49\x32\xde\xe7\xef\x89\x70\xd4\xc4\x1f\xd5\xf7\x26\xab\x54" +
+	"\xea\x42\x6f\x17\x1d\xcb\x8e\x0e\x30\x3b\xec\x56\xe2\x09\x45\xff\xf8\x33\x7e\x9a\x44\xb6\x2f\x8b\xb7\xf2\x52\xbd" +
+	"\x2c\xd3\x1b\xd8\x37\x01\x63\x87\x1f\x46\xf6\xf6\xf4\x58\x44\x4f\xda\xfc\xf0\x51\xd5\x4b\x60\x5c\xd1\xf5\xf0\x84" +
+	"\x2a\xa7\x10\x89\xc7\x29\x61\xb1\x2b\x5f\x80\xef\xe4\xaa\x3a\x92\x90\x1d\x41\x66\x14\x36\xde\xf2\x9a\x8d\x29\xb0" +
+	"\x0b\x68\x18\x74\x3a\x52\xbc\x7f\x05\xdf\x60\x35\x2a\x42\xf1\x54\x78\x60\xd8\x8d\x61\x67\xd4\x5a\x16\xb3\xfa\xbb" +
+	"\xd2\x36\x96\x32\x88\x22\x25\x20\x35\x1b\xf7\xe0\xda\x90\x91\x41\xc8\x51\xd7\x9d\x93\x90\x3b\xec\xb1\x1d\x82\xd2" +
+	"\x61\x8b\x63\x4f\x70\x90\xc3\x07\xf0\xa8\x68\x20\x36\x9c\xd2\xa9\xb3\x53\xf2\xc1\xb3\xc9\x36\x97\x32\x5b\xc7\x96" +
+	"\x11\x38\x81\xb4\x30\xae\xa7\x24\x3c\x12\x03\x4a\xcd\xc4\x7a\x63\x99\x19\xf8\x03\xe9\x97\x2c\xb3\x67\x19\xc1\xc8" +
+	"\xcf\xca\xbc\x13\xb2\x22\x66\x75\xa8\x3d\xac\xbb\x20\xba\x3d\xa3\xf7\x1a\x2e\x59\xe6\x32\x51\x11\xac\x52\xbc\xff" +
+	"\x7a\xfb\x95\x4c\xcc\x9c\x36\xea\x83\xf5\xd5\x57\xca\x0c\x2d\xc8\xd9\xe0\x44\xfc\x32\xb6\xa8\x9d\x93\x75\x52\x04" +
+	"\x44\x8b\xef\x48\x32\x4a\xf1\x0b\x94\x29\xc6\xcc\xd1\xf4\x27\xb4\xb8\x9f\x67\x60\x74\x31\xb2\x7e\xa8\xf4\x45\x89" +
+	"\xe7\x20\x63\xa0\xac\x81\xb0\xf1\x38\xe8\xc4\x94\x7b\x89\xa1\x2f\x85\xef\x8d\xaa\x6e\x9c\x18\x90\xa4\x3d\x01\x9f" +
+	"\xf1\x8b\x34\xfd\x27\x82\x38\x07\xb7\x49\x70\x80\x55\xc3\xa7\x5f\x33\x78\x08\x2e\x94\x4c\xc1\xec\x06\x31\xbc\x25" +
+	"\xd8\x43\x50\xb4\x92\xf4\x04\x79\x47\x4d\x36\xea\x06\x07\x10\x8f\xc9\xb2\x60\xe2\x08\x22\xe7\xfd\x53\xe8\x0c\x1c" +
+	"\x1c\x45\xbc\x5e\x75\x10\x72\xf6\x53\xef\x9a\x9f\xf6\x84\x3d\xfc\x3e\xcb\x29\xc8\x99\xa0\xea\x2a\xc0\xbe\x7c\xef" +
+	"\x21\x60\x28\xfd\xe3\xe3\x25\xd8\xa1\x9e\x45\xe1\xe3\x4b\x96\x87\x27\x89\xb1\xc3\xe2\xd7\x68\x03\x92\xbe\x8f\xa8" +
+	"\x1f\x80\xac\x4d\x14\x3d\x43\x1e\x0c\xa5\x46\x68\x14\x4f\x7f\x6a\xa3\xcc\x30\x55\x64\xfb\x85\xde\x41\x69\xda\x0a" +
+	"\xef\x50\xa5\x99\x34\x81\x35\xc4\x02\x2d\xbd\x31\xa1\x0c\xef\x1c\xb7\xa4\x78\x94\xa0\xdb\x70\x8c\x7d\xa9\x66\x65" +
+	"\xa5\x22\x2f\x44\x4e\x8c\xda\x27\x88\x7c\xcc\x0e\x58\x2f\x29\x64\xa3\x4d\x20\x45\xaf\xca\xc8\x84\x47\x5c\x04\xaa" +
+	"\x0b\xd0\x2c\x91\x6d\x32\x3b\x3c\x4f\xf8\x83\x3b\x93\xf8\x4b\x25\x97\x11\xfc\x06\x9a\xd1\xe5\x54\xad\xa1\x4c\xcc" +
+	"\x72\x95\x86\x2e\x92\x61\x5d\x95\xaa\x04\xfa\xcf\xb4\x9f\x31\x02\x46\xe7\x42\x2e\x97\xa1\x69\x75\xb3\x53\x35\x45" +
+	"\xc8\x89\x04\xd3\xeb\xc9\x85\x42\x0a\x09\xc1\x95\xac\x05\xe9\x0c\x2e\xcc\x8a\x5a\x83\x0c\x45\x09\x92\x56\x58\x85" +
+	"\xd4\x18\x32\x3c\x8a\x99\x47\x83\x9f\x68\xb7\x74\x01\x5a\x04\x42\xad\x2d\x75\x53\x53\x7f\xfc\x5c\xfc\xf6\xec\x99" +
+	"\xc9\x06\x33\x8a\x41\x07\x35\xc8\x5c\xc9\x05\x8a\x1d\x77\x1b\x8a\x90\x24\x07\x40\x1d\x67\x50\x79\xc8\x4a\x39\x46" +
+	"\x81\x44\x28\x90\x17\xb8\x58\x65\x97\x4a\x34\x35\x6e\xfc\x4a\x19\x83\xce\xd9\x2c\xfa\x35\x36\x56\xb1\x99\x5a\x26" +
+	"\xd7\x6a\xc5\x44\xcc\x4f\xc5\x42\x56\xf5\x85\xcc\xed\x45\x11\x9f\xfd\xd9\x1c\x1e\xff\xbd\xa7\xc3\xfb\x28\xd1\x5d" +
+	"\x9b\x23\xbd\x95\xb9\xdb\xd7\x29\x19\xdd\x8a\x9d\xef\xb6\x27\x68\xff\xda\xd7\xf8\x05\x7e\x85\x3d\xb2\x78\x9c\xee" +
+	"\x8a\xc7\x75\x38\x59\x97\xb9\x5b\x20\xcb\x1e\x3b\xab\x91\x33\xad\x1c\xb3\x6c\xd2\x2f\x70\x69\x93\x45\x7b\x72\x33" +
+	"\x26\xcc\x37\xf8\x02\xb0\xca\xd9\x63\x18\xbb\x6d\xa1\x3a\x26\x89\x9d\xa4\xaa\x66\x90\xfd\x6e\x57\x18\x4a\xe2\xa8" +
+	"\x67\xb3\x63\x3f\x10\x9b\x4c\xb6\x51\x7b\xeb\x0d\x8b\xc9\x56\x57\xd4\x3f\x51\xaa\xf2\x5b\xb2\xff\xbc\x32\x74\x95" +
+	"\xe9\x7f\xa3\x46\x1c\xb3\x2a\x49\x7a\x52\x83\x3d\x2f\x35\x92\xfc\x0c\x1e\x21\xba\x08\xbb\xf4\x9c\x48\x66\x96\xe2" +
+	"\x17\xbc\x58\xbb\x8c\x60\x76\x3d\x7d\x1a\xb0\xb5\x74\x64\x64\x2c\x7a\x88\xe5\x96\x93\x0e\xf3\x3f\xc2\x6c\x8f\xcb" +
+	"\xb1\xe7\x50\x43\x2e\xd2\x77\x8c\xfd\xb2\xc9\x53\x51\x94\x5a\x24\xe0\x1c\xc2\xe8\xcf\x75\x05\xd6\x35\xf0\x13\x03" +
+	"\x9e\x4c\x74\x23\x73\xe1\x19\x93\x85\x2c\xa4\x4e\x2e\xb8\xa7\xec\x74\x36\xb4\x6e\x4c\xe2\x90\x7f\xbb\x66\x86\x6f" +
+	"\x63\x69\xb1\x47\x40\xa8\xa4\x4d\x9f\xb1\xa3\x21\xd7\x6f\x8d\x81\xb2\xc7\xb1\xca\x55\xa2\x39\xcb\x99\x74\xff\x22" +
+	"\xcf\xa1\x25\xd7\x58\xe1\x45\xe3\x8e\xb7\x0c\xcb\xe2\x3e\xc2\x98\x2b\x2d\x2c\xe5\xd4\x5d\xb1\x20\x8c\x24\x46\x04" +
+	"\xf2\xe9\x26\xa2\x39\x0b\x9f\x60\x2c\x27\xfa\x4e\xcf\xce\x6f\xb4\x22\x47\x7b\x7d\xbd\x04\xb2\xc1\x47\xff\xe4\x10" +
+	"\x3f\x13\xe1\xe3\xef\xe8\x87\x40\x30\x4b\xe1\x47\xe8\xfd\x62\x28\x64\xd9\x63\x30\x6b\x2a\x47\xa9\xab\x23\x18\x6a" +
+	"\xee\x72\xb5\x22\xc5\x2a\x9c\xff\x98\x53\xae\xbc\x58\x43\x67\xa3\x1d\xbb\x33\x58\x49\x81\xf2\x81\x50\x0f\x77\x42" +
+	"\xcb\x8e\xc6\x09\x3e\x87\x57\x9d\xab\x44\x36\x80\xf2\x31\xe4\x88\x9a\x83\x62\x4f\x65\xdb\x65\xe1\x48\xf4\xc6\x47" +
+	"\xf0\x77\x0e\xd8\xbe\xb5\x30\x57\xb1\x8c\x56\xdd\xf2\x91\x9b\x18\x42\x46\xdc\x26\x12\x6e\x79\xbe\x83\x13\xd2\x98" +
+	"\x83\x2c\x4f\x98\x72\x89\x85\xcc\x13\x5a\x3f\x06\x6b\x29\xd2\xf1\xae\xb7\x1f\x9c\x11\x2a\x86\x42\x45\xbc\x6a\xe5" +
+	"\x81\xcb\x20\x8c\xa8\x4d\x16\x26\xdb\xda\x70\xf1\x8f\xbd\xad\xf1\xa2\x2b\xd6\xf7\xea\x2a\x0a\x0f\xe5\x75\xb6\x68" +
+	"\x16\xf6\x86\x5a\xa8\xeb\x44\xa9\xd4\xaf\x32\xda\xdc\xd6\x8f\xb9\x58\x9c\x79\x83\xda\xa0\x6d\xd7\xe1\xe9\xa3\x9a" +
+	"\x67\x50\x43\x54\x04\xac\xcc\x03\xe4\x6d\x7c\x34\xb9\xa6\x16\x18\xed\x34\x94\x20\x2f\x8e\x0e\xfe\x52\x0b\x4a\x68" +
+	"\xb5\x4a\x9a\x2a\xd3\x37\x78\x5b\x0d\xd6\xb5\x50\x75\x2c\x0e\x1b\xb0\xe9\x73\x45\x31\x02\x14\xf7\x84\xf2\xcc\x13" +
+	"\xce\xfb\x07\x45\xa6\xa3\x31\xe4\xfb\xb5\xe9\x8a\x4f\x00\x4f\xc1\xec\x64\x38\xfe\x54\xab\x63\xbe\x3a\xda\x30\x51" +
+	"\xd9\x59\x1b\x8c\x30\x25\x14\x44\x7c\x8e\x37\xcc\x43\xcc\x6e\x33\x10\xf1\xcb\x5c\xb0\xb1\x8e\x14\x37\xd0\x1c\xd5" +
+	"\xdd\x31\x19\x91\x5f\x95\xa5\xb6\x99\xfb\x23\xfc\xe6\xa1\x5f\xdd\xed\xed\x69\xd3\x9e\xf8\x99\x26\xd5\x10\xbb\x08" +
+	"\x42\xfa\x07\xa6\x77\x3b\xbd\x3e\x4f\x6b\x68\x7c\xc2\x80\xf8\xc4\xb4\x2a\x0c\x79\x57\x16\xf3\x5d\x13\x5d\xaa\x6f" +
+	"\x69\x79\x55\x44\x83\x23\xd2\x49\xe0\x2a\xe6\xfe\xbc\x61\x4f\xe8\xaa\x51\x81\x5f\xd9\x58\xfa\xcd\xe4\x66\x6f\x0d" +
+	"\xb7\xbf\x03\x49\x70\x11\x6e\x1b\x0d\xc1\x88\x07\xb2\x64\x93\x9d\x61\x2c\xba\x03\x4a\x6d\xa3\x44\x70\x43\x57\x14" +
+	"\x78\x9e\x4c\x53\x8a\x44\x55\x5a\x66\xe0\x35\x97\x50\xff\x0a\x58\xb2\xa1\x06\xbb\x00\xc1\xce\x01\x46\xe3\x0b\x2c" +
+	"\x7c\x99\x97\xc9\x37\xf4\x25\xb0\x64\x22\x10\xe5\x00\x81\x09\xaa\xeb\x92\xeb\x3f\x5d\x0a\x48\x70\x19\x94\xb7\x68" +
+	"\xd0\x37\x02\xcc\x25\xf9\xf6\x03\x18\x57\x46\xe1\xd8\xf2\x18\xc6\x22\x64\x67\xad\x85\xdf\x50\x14\x8d\xb8\x2c\x8a" +
+	"\x6c\xed\x6d\x0a\x15\x37\xa9\xc1\x10\xc1\x21\x31\x59\xa4\x1b\x44\xe8\x99\x15\x7a\x55\x3b\x03\xb4\xfd\x2a\x64\xc7" +
+	"\x4c\xd6\xde\xfc\x70\x64\x16\x76\x21\x3d\xf9\xb3\xc8\x51\xa7\xdf\xee\x9d\x62\x47\x8b\x3d\x04\xeb\xfd\xa3\x9d\x35" +
+	"\x0e\x00\x7c\x1b\x77\xa3\x75\xb3\x77\x93\x95\x93\xe9\x59\xf3\x46\xb2\xab\x39\xd0\xcc\x12\x38\xcc\x0a\x8c\x9b\xef" +
+	"\x71\x0d\x63\x7a\xae\x8a\xad\x45\x93\xbd\xe3\x63\x53\xec\x0a\x14\x3a\x4e\xdf\xc5\x93\x8e\x38\x21\x0f\xc3\x6d\x4e" +
+	"\x28\x56\x29\x7e\x7f\x72\x47\x01\x8b\xa3\x9d\x5e\xe1\xfa\xe8\xba\x33\xbf\xd9\x42\x24\xa2\x3f\x45\x14\xd7\xf0\x70" +
+	"\xd6\x2f\xed\x06\x1a\x1f\xf8\xcb\xcb\x79\xfc\x46\x6a\x99\x47\x63\xca\x4e\xb0\x67\x1c\x1f\xd6\xf3\x28\xa4\xc4\x4d" +
+	"\x05\x1d\x9a\xeb\xd8\xaa\x28\xf0\x35\xc5\x4f\xb8\xc7\x37\x61\xf3\x46\x83\x73\x2b\x66\xa6\x9c\x2a\x05\xfb\xba\xac" +
+	"\x65\xc2\x4e\x0e\xa2\x71\x77\xc6\xec\xa7\xe4\x7b\x8e\x9a\xbb\xbe\x30\xec\x0a\xb6\xd4\x54\xd7\x12\x68\x02\xeb\x64" +
+	"\x55\x05\xdd\x82\x93\x87\x9d\x50\x65\x98\x4d\xc6\x08\xdd\xa1\xa7\x10\xf0\x04\xcd\x59\x5d\x98\x33\x8c\x63\xef\x05" +
+	"\x14\x3c\x05\x20\xea\xc1\xd1\x6b\x3c\x8b\x16\x15\x6a\xe7\x5f\x45\xd8\xaf\x3c\xb6\x38\xe9\x06\x1f\xdd\xe4\xa2\x1b" +
+	"\x3d\x74\xab\x83\xf6\xfc\x73\xdd\x0b\x57\x93\x81\xa9\xcf\x36\xdf\xbc\xa7\x6b\x5a\x36\xde\x66\x69\xaa\x0a\x87\x8e" +
+	"\x1f\x77\xa9\xe4\x73\xa0\x41\x12\x8c\xaa\x76\x9d\x62\x79\xd7\x9d\x1e\xbf\xc9\xcf\x0d\x41\x60\xfd\xf1\x87\x46\x2f" +
+	"\x1b\xfd\xa6\xac\xa0\x08\x86\x1c\x87\x43\xe9\xfd\x32\x6f\x16\x45\x2b\xb9\xa3\x4a\x41\x5c\x78\x7d\xff\xc0\xd0\xf6" +
+	"\x8e\x3e\xf7\x1d\x4c\xad\x56\xa0\x8a\x7a\xa5\x66\xb2\xc9\x21\x97\xd0\x0e\x4c\x33\xb8\x85\x4b\x2f\x28\x94\x9d\x99" +
+	"\x4d\xc4\xd5\x45\x06\x55\x35\xac\x51\x42\x52\xa9\x77\x89\xc9\x3f\xda\x9b\x17\xc1\x3e\x37\x15\xc2\x3f\x76\x15\x68" +
+	"\x85\x0c\xc2\x28\x64\x8c\x3b\x8c\xd1\x54\xdf\x1d\x3a\x37\x54\xe0\x6b\x73\x40\x9f\xcd\x41\x11\x76\xd8\x4c\x0c\x90" +
+	"\x18\xc4\xdd\x13\xb1\x7f\xfc\x99\x92\xf0\x09\x7c\x33\x4d\x5b\x88\x36\xe7\xa9\x7f\x5a\x4b\x50\xbe\x03\x6c\x20\x84" +
+	"\xa7\xc2\x3d\xc6\x26\x9d\x2b\x3a\x49\x6b\xb4\x85\x6b\x5b\xfa\x83\x0b\x40\x47\x9a\xa5\x10\x07\x59\x72\x26\x31\x6f" +
+	"\xf0\xa9\x1f\xc9\x33\x56\xc0\xfd\x69\x2b\x34\x40\xf0\x30\xf1\xa7\x64\x34\x51\x4c\xcd\x8c\xb9\x33\x50\xe5\xeb\x4f" +
+	"\x87\x5d\x64\xb5\xda\x3d\x33\x84\x0f\x36\x9a\x5b\xb2\xc9\xa7\x02\xe5\x8d\x95\x13\xb6\xc6\x48\x90\x4d\x29\xab\x60" +
+	"\x58\x78\xf7\x9f\x88\x3c\x30\x87\x3e\x34\x73\x6e\x79\x23\xd8\xbe\x0a\xdc\x26\x15\x33\x51\xed\xe5\x5e\xee\x7e\x92" +
+	"\xbc\x49\x95\x9d\x56\xe2\xe0\x9b\x07\xaa\xdd\x7c\x7b\x67\x9e\x4e\xcc\x68\x72\x6d\xf2\xe2\x26\x0e\x6b\x63\x52\xb6" +
+	"\xc2\xb5\x19\xe9\x96\x11\xe9\x36\x02\xf8\x2e\xac\x12\x3a\xf3\xd0\x16\xf5\x50\x59\x6f\x46\xfe\x47\x2e\x51\x0f\x0d" +
+	"\x63\xda\xe0\x31\xf8\x36\xcb\xcb\xb0\x83\xef\xb5\x3a\x38\x4e\xc3\xde\x8b\xba\xf0\x0c\x47\x9e\x56\xf9\xd8\xf8\x9c" +
+	"\xb5\x08\xbb\x8e\xbc\x79\x76\x35\x60\xb9\x9b\xfe\x5f\x89\x51\x43\xfb\x96\xe9\xce\xff\x5c\xd2\x56\x91\xa3\xab\xcd" +
+	"\x35\x4d\x17\x27\x59\x3b\x8f\x31\x4a\x6e\x1a\x30\xe9\xf9\x21\xee\xe1\x63\xb3\xf6\x3e\x0a\x11\x4e\xa8\x6b\xd3\xb2" +
+	"\xcd\x2e\xb0\xd5\x80\xee\x1e\x9b\xf9\xd6\x84\x0c\x54\x73\xeb\xae\xc8\x60\xff\x6d\x95\x3f\x12\x1b\x0c\xcc\x9d\x17" +
+	"\xc5\xe9\xba\xc0\x7a\x56\xf4\xff\x2f\x32\x63\x3e\x98\x29\x29\xea\x03\xd7\x26\xdc\xdf\x29\xaa\xed\x0d\x8c\x31\xda" +
+	"\x7b\xc5\xe0\x21\xab\xf4\x74\xe9\x45\xde\xab\xad\x4d\xcb\x16\xc1\x98\x31\x23\xd5\x58\xad\x24\x56\xdd\x68\xba\x96" +
+	"\x6e\x39\x09\xd3\xef\xf5\x6e\xdc\xb5\x32\xc3\x9d\x8c\x79\xd7\x11\x8d\xfd\x9e\x65\x15\xfc\x1b\x7f\xf6\xfb\x00\x58" +
+	"\x2a\x00\x00")
 
 func bindataTemplatesCommandstmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -191,10 +210,10 @@ func bindataTemplatesCommandstmpl() (*asset, error) {
 
 	info := bindataFileInfo{
 		name: "templates/commands.tmpl",
-		size: 8963,
+		size: 10840,
 		md5checksum: "",
 		mode: os.FileMode(420),
-		modTime: time.Unix(1552262930, 0),
+		modTime: time.Unix(1792380391, 0),
 	}
 
 	a := &asset{bytes: bytes, info: info}
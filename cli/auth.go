@@ -0,0 +1,257 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// reloadCreds discards loaded credentials and reads them again from storage.
+func reloadCreds() {
+	Creds.Viper = loadSettings("credentials")
+}
+
+// updateProfiles calls the given function with the `profiles` map from the
+// stored settings, then saves the result and reloads it. Viper cannot remove
+// keys once they are loaded, so this is used whenever something is deleted.
+// The settings are locked so that changes from other processes are kept.
+func updateProfiles(name string, reload func(), update func(profiles map[string]interface{})) error {
+	unlock, err := Lock(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	settings, err := readSettings(name)
+	if err != nil {
+		return err
+	}
+
+	profiles, _ := settings["profiles"].(map[string]interface{})
+	if profiles == nil {
+		profiles = make(map[string]interface{})
+	}
+
+	update(profiles)
+	settings["profiles"] = profiles
+
+	if err := writeSettings(name, settings); err != nil {
+		return err
+	}
+
+	reload()
+	return nil
+}
+
+// credsProfileName normalizes a profile name given on the command line and
+// ensures that it exists in the credentials file.
+func credsProfileName(name string) (string, error) {
+	name = strings.Replace(name, ".", "-", -1)
+	if !Creds.IsSet("profiles." + name) {
+		return "", fmt.Errorf("unknown profile %s", name)
+	}
+
+	return name, nil
+}
+
+// ClearCachedTokens removes any cached tokens for the named profile, e.g.
+// `profiles.<name>.token`, from `cli.Cache`.
+func ClearCachedTokens(name string) error {
+	return updateProfiles("cache", ReloadCache, func(profiles map[string]interface{}) {
+		delete(profiles, name)
+	})
+}
+
+// loginProfile replaces any cached tokens for the named profile by fetching a
+// new one through the auth handler, unless the handler provides its own way
+// to log in. The profile must be the current one.
+func loginProfile(name string, handler AuthHandler) error {
+	if login, ok := handler.(AuthLoginHandler); ok {
+		l := log.With().Str("profile", name).Logger()
+		return login.Login(&l)
+	}
+
+	if err := ClearCachedTokens(name); err != nil {
+		return err
+	}
+
+	_, err := authorize(name, handler)
+	return err
+}
+
+// authorize runs the auth handler for the named profile, which must be the
+// current one, and returns the resulting request. Handlers acquire and cache
+// tokens when a request is made, so this makes a request which is never sent
+// to trigger it.
+func authorize(name string, handler AuthHandler) (*http.Request, error) {
+	req, err := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	l := log.With().Str("profile", name).Logger()
+	if err := handler.OnRequest(&l, req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// maskProfile returns a copy of the profile with secret values hidden.
+func maskProfile(profile map[string]interface{}) map[string]interface{} {
+	masked := make(map[string]interface{}, len(profile))
+	for k, v := range profile {
+		if isSensitive(k) {
+			v = hidden
+		}
+		masked[k] = v
+	}
+
+	return masked
+}
+
+// initAuthProfileCommands sets up the commands to manage and log into
+// existing auth profiles.
+func initAuthProfileCommands() {
+	authCommand.AddCommand(&cobra.Command{
+		Use:     "remove-profile <name>",
+		Aliases: []string{"rm"},
+		Short:   "Remove an authentication profile and its cached tokens",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, err := credsProfileName(args[0])
+			if err != nil {
+				return err
+			}
+
+			if err := updateProfiles("credentials", reloadCreds, func(profiles map[string]interface{}) {
+				delete(profiles, name)
+			}); err != nil {
+				return err
+			}
+
+			return ClearCachedTokens(name)
+		},
+	})
+
+	authCommand.AddCommand(&cobra.Command{
+		Use:     "rename-profile <name> <new-name>",
+		Aliases: []string{"mv"},
+		Short:   "Rename an authentication profile",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, err := credsProfileName(args[0])
+			if err != nil {
+				return err
+			}
+
+			newName := strings.Replace(args[1], ".", "-", -1)
+			if Creds.IsSet("profiles." + newName) {
+				return fmt.Errorf("profile %s already exists", newName)
+			}
+
+			rename := func(profiles map[string]interface{}) {
+				if profile, ok := profiles[name]; ok {
+					profiles[newName] = profile
+					delete(profiles, name)
+				}
+			}
+
+			if err := updateProfiles("credentials", reloadCreds, rename); err != nil {
+				return err
+			}
+
+			// Keep cached tokens so there is no need to log in again.
+			return updateProfiles("cache", ReloadCache, rename)
+		},
+	})
+
+	authCommand.AddCommand(&cobra.Command{
+		Use:   "show-profile [name]",
+		Short: "Show an authentication profile with secrets masked",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := profileName()
+			if len(args) > 0 {
+				name = args[0]
+			}
+
+			name, err := credsProfileName(name)
+			if err != nil {
+				return err
+			}
+
+			return Formatter.Format(maskProfile(Creds.GetStringMap("profiles." + name)))
+		},
+	})
+
+	authCommand.AddCommand(&cobra.Command{
+		Use:   "set-default <name>",
+		Short: "Set the default authentication profile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, err := setDefaultProfile(args[0])
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(Stdout, "Default profile set to %s\n", name)
+			return nil
+		},
+	})
+
+	authCommand.AddCommand(&cobra.Command{
+		Use:   "login",
+		Short: "Log in with the current profile, replacing any cached tokens",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, err := credsProfileName(profileName())
+			if err != nil {
+				return err
+			}
+
+			handler := AuthHandlers[GetProfile()["type"]]
+			if handler == nil {
+				return fmt.Errorf("no handler for auth type %s", GetProfile()["type"])
+			}
+
+			if err := loginProfile(name, handler); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(Stdout, "Logged in with profile %s\n", name)
+			return nil
+		},
+	})
+
+	authCommand.AddCommand(&cobra.Command{
+		Use:   "logout",
+		Short: "Revoke and clear cached tokens for the current profile",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, err := credsProfileName(profileName())
+			if err != nil {
+				return err
+			}
+
+			if handler, ok := AuthHandlers[GetProfile()["type"]].(AuthLogoutHandler); ok {
+				// Cached tokens are cleared even if they can't be revoked, e.g.
+				// because they have already expired.
+				l := log.With().Str("profile", name).Logger()
+				if err := handler.Logout(&l); err != nil {
+					l.Warn().Err(err).Msg("Could not revoke tokens")
+				}
+			}
+
+			if err := ClearCachedTokens(name); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(Stdout, "Logged out of profile %s\n", name)
+			return nil
+		},
+	})
+}
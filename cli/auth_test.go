@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// testAuth is an auth handler which caches a token on each request.
+type testAuth struct{}
+
+func (a *testAuth) ProfileKeys() []string {
+	return []string{"client_id", "client_secret"}
+}
+
+func (a *testAuth) OnRequest(log *zerolog.Logger, request *http.Request) error {
+	Cache.Set("profiles."+profileName()+".token", "abc")
+	return SaveCache()
+}
+
+func TestAuthProfileCommands(t *testing.T) {
+	setupTest(t)
+	UseAuth("test", &testAuth{})
+
+	execute("auth add-profile test default id1 secret1")
+	execute("auth add-profile test other id2 secret2")
+	assert.Equal(t, "id1", Creds.GetString("profiles.default.client_id"))
+
+	out := execute("auth show-profile other")
+	assert.JSONEq(t, `{"type": "test", "client_id": "id2", "client_secret": "**HIDDEN**"}`, out)
+
+	assert.Equal(t, "Logged in with profile default\n", execute("auth login"))
+	assert.Equal(t, "abc", Cache.GetString("profiles.default.token"))
+
+	assert.Equal(t, "Logged out of profile default\n", execute("auth logout"))
+	assert.False(t, Cache.IsSet("profiles.default.token"))
+
+	execute("auth rename-profile other renamed")
+	assert.False(t, Creds.IsSet("profiles.other"))
+	assert.Equal(t, "id2", Creds.GetString("profiles.renamed.client_id"))
+
+	execute("auth remove-profile renamed")
+	assert.False(t, Creds.IsSet("profiles.renamed"))
+	assert.True(t, Creds.IsSet("profiles.default"))
+
+	assert.Contains(t, execute("auth show-profile missing"), "unknown profile missing")
+}
+
+func TestAddProfilePrompt(t *testing.T) {
+	setupTest(t)
+	UseAuth("test", &testAuth{})
+
+	defer func() { Stdin = os.Stdin }()
+
+	// Not enough input fails without saving anything.
+	Stdin = strings.NewReader("")
+	assert.Contains(t, execute("auth add-profile test empty"), "missing value for Client id")
+	assert.False(t, Creds.IsSet("profiles.empty"))
+
+	// Missing values are read from stdin, one per line.
+	Stdin = strings.NewReader("piped-secret\n")
+
+	execute("auth add-profile test piped --client-id id1 --validate")
+	assert.Equal(t, "id1", Creds.GetString("profiles.piped.client_id"))
+	assert.Equal(t, "piped-secret", Creds.GetString("profiles.piped.client_secret"))
+	assert.Equal(t, "abc", Cache.GetString("profiles.piped.token"))
+}
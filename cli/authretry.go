@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/rs/zerolog"
+)
+
+// authRetryTransport wraps an HTTP round tripper and lets the auth handler
+// inspect each response. If the handler asks for it, e.g. because an expired
+// token was rejected, then the request is authorized again and sent once
+// more.
+type authRetryTransport struct {
+	transport http.RoundTripper
+	log       *zerolog.Logger
+	handler   AuthHandler
+}
+
+// RoundTrip sends the request, retrying it once if the auth handler asks.
+func (t *authRetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// The body can only be read once, so keep a copy around in case the
+	// request needs to be sent again.
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	retry, err := t.handler.(AuthResponseHandler).OnResponse(t.log, req, resp)
+	if err != nil {
+		t.log.Warn().Err(err).Msg("Auth response handler failed")
+		return resp, nil
+	}
+
+	if !retry {
+		return resp, nil
+	}
+
+	drainBody(resp.Body)
+
+	// Authorize a copy of the request from scratch, without the rejected
+	// credentials.
+	retryReq := req.WithContext(req.Context())
+	retryReq.Header = make(http.Header, len(req.Header))
+	for k, v := range req.Header {
+		retryReq.Header[k] = v
+	}
+	retryReq.Header.Del("Authorization")
+
+	if body != nil {
+		retryReq.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	if err := t.handler.OnRequest(t.log, retryReq); err != nil {
+		return nil, err
+	}
+
+	if signer, ok := t.handler.(AuthSigner); ok {
+		if err := signer.Sign(t.log, retryReq); err != nil {
+			return nil, err
+		}
+	}
+
+	t.log.Info().Msg("Retrying request with new credentials")
+
+	return t.transport.RoundTrip(retryReq)
+}
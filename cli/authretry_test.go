@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// lifecycleAuth is an auth handler implementing all of the optional hooks.
+type lifecycleAuth struct {
+	token    string
+	loggedIn bool
+}
+
+func (a *lifecycleAuth) ProfileKeys() []string {
+	return []string{"user"}
+}
+
+func (a *lifecycleAuth) OnRequest(log *zerolog.Logger, request *http.Request) error {
+	if request.Header.Get("Authorization") == "" {
+		request.Header.Set("Authorization", "Bearer "+a.token)
+	}
+	return nil
+}
+
+func (a *lifecycleAuth) OnResponse(log *zerolog.Logger, request *http.Request, response *http.Response) (bool, error) {
+	if response.StatusCode == http.StatusUnauthorized {
+		a.token = "fresh"
+		return true, nil
+	}
+	return false, nil
+}
+
+func (a *lifecycleAuth) Login(log *zerolog.Logger) error {
+	a.loggedIn = true
+	return nil
+}
+
+func (a *lifecycleAuth) ValidateProfile(profile map[string]string) error {
+	if profile["user"] == "invalid" {
+		return errors.New("bad user")
+	}
+	return nil
+}
+
+func TestAuthLifecycleHooks(t *testing.T) {
+	setupTest(t)
+
+	handler := &lifecycleAuth{token: "stale"}
+	UseAuth("lifecycle", handler)
+
+	assert.Contains(t, execute("auth add-profile lifecycle default invalid"), "invalid profile: bad user")
+	assert.False(t, Creds.IsSet("profiles.default"))
+
+	execute("auth add-profile lifecycle default user1")
+	assert.Equal(t, "user1", Creds.GetString("profiles.default.user"))
+
+	execute("auth login")
+	assert.True(t, handler.loggedIn)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.JSONEq(t, `{"hello": "world"}`, string(body))
+
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	// The rejected request is sent again once with new credentials.
+	res, err := Client.Post().URL(server.URL).JSON(map[string]string{"hello": "world"}).Do()
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, 2, requests)
+
+	// Requests are only retried once.
+	handler.token = "stale"
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	requests = 0
+	res, err = Client.Get().URL(server.URL).Do()
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	assert.Equal(t, 2, requests)
+}
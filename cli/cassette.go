@@ -0,0 +1,418 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/viper"
+	"gopkg.in/h2non/gentleman.v2/context"
+)
+
+// cassetteBody stores a request or response body. Text bodies are stored as
+// is to keep cassettes readable, while binary bodies are base64 encoded.
+type cassetteBody struct {
+	Body       string `json:"body,omitempty"`
+	BodyBase64 string `json:"body_base64,omitempty"`
+}
+
+// setBody stores the given body data.
+func (b *cassetteBody) setBody(data []byte) {
+	if utf8.Valid(data) {
+		b.Body = string(data)
+	} else {
+		b.BodyBase64 = base64.StdEncoding.EncodeToString(data)
+	}
+}
+
+// bytes returns the stored body data.
+func (b *cassetteBody) bytes() ([]byte, error) {
+	if b.BodyBase64 != "" {
+		return base64.StdEncoding.DecodeString(b.BodyBase64)
+	}
+
+	return []byte(b.Body), nil
+}
+
+// CassetteRequest is a recorded HTTP request.
+type CassetteRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	cassetteBody
+}
+
+// CassetteResponse is a recorded HTTP response.
+type CassetteResponse struct {
+	Status  int         `json:"status"`
+	Headers http.Header `json:"headers,omitempty"`
+	cassetteBody
+}
+
+// Interaction is a single recorded request and its response. A cassette
+// file contains one JSON-encoded interaction per line.
+type Interaction struct {
+	Recorded time.Time        `json:"recorded"`
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+// recordTransport wraps an HTTP round tripper and appends each interaction
+// to a cassette file. Secrets are scrubbed before being written.
+type recordTransport struct {
+	transport http.RoundTripper
+	path      string
+	secrets   []string
+}
+
+// RoundTrip sends the request and records it together with its response.
+// Streaming responses are recorded once they have been read, so that items
+// are still passed on as they arrive.
+func (t *recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	interaction := Interaction{
+		Recorded: time.Now().UTC(),
+		Request: CassetteRequest{
+			Method:  req.Method,
+			URL:     redactURL(req.URL, t.secrets).String(),
+			Headers: redactHeaders(req.Header, t.secrets),
+		},
+		Response: CassetteResponse{
+			Status:  resp.StatusCode,
+			Headers: redactHeaders(resp.Header, t.secrets),
+		},
+	}
+	interaction.Request.setBody(reqBody)
+
+	if resp.StatusCode < 400 && isStreamingType(resp.Header) {
+		resp.Body = &recordingBody{
+			ReadCloser: resp.Body,
+			record: func(data []byte) error {
+				interaction.Response.setBody(data)
+				return appendInteraction(t.path, &interaction)
+			},
+		}
+		return resp, nil
+	}
+
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	interaction.Response.setBody(respBody)
+
+	if err := appendInteraction(t.path, &interaction); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// recordingBody passes a response body through while keeping a copy of it,
+// which is recorded when the body is closed.
+type recordingBody struct {
+	io.ReadCloser
+	buf    bytes.Buffer
+	record func(data []byte) error
+	once   sync.Once
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.buf.Write(p[:n])
+	return n, err
+}
+
+func (b *recordingBody) Close() error {
+	err := b.ReadCloser.Close()
+
+	b.once.Do(func() {
+		if recordErr := b.record(b.buf.Bytes()); err == nil {
+			err = recordErr
+		}
+	})
+
+	return err
+}
+
+// appendInteraction writes an interaction as a new line in the cassette file,
+// creating it if needed.
+func appendInteraction(path string, interaction *Interaction) error {
+	encoded, err := json.Marshal(interaction)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(encoded, '\n'))
+	return err
+}
+
+// LoadCassette reads all interactions from a cassette file.
+func LoadCassette(path string) ([]*Interaction, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	interactions := make([]*Interaction, 0)
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		interaction := &Interaction{}
+		if err := json.Unmarshal(scanner.Bytes(), interaction); err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", path, line, err)
+		}
+
+		interactions = append(interactions, interaction)
+	}
+
+	return interactions, scanner.Err()
+}
+
+// replayTransport serves recorded responses without using the network.
+type replayTransport struct {
+	sync.Mutex
+	interactions []*Interaction
+	used         map[*Interaction]bool
+	match        []string
+}
+
+// RoundTrip returns the first unused recorded response matching the request.
+// Once all matching interactions are used, the last one is served again.
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	var found *Interaction
+	for _, interaction := range t.interactions {
+		ok, err := matchInteraction(t.match, req, body, interaction)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			found = interaction
+			if !t.used[interaction] {
+				break
+			}
+		}
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("no recorded interaction matches %s %s", req.Method, req.URL)
+	}
+
+	t.used[found] = true
+
+	return found.Response.httpResponse(req)
+}
+
+// httpResponse creates a new HTTP response for the request from the recorded
+// status, headers and body.
+func (r *CassetteResponse) httpResponse(req *http.Request) (*http.Response, error) {
+	data, err := r.bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	header := http.Header{}
+	for name, values := range r.Headers {
+		header[name] = append([]string(nil), values...)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status)),
+		StatusCode:    r.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}, nil
+}
+
+// matchInteraction returns whether a request matches a recorded interaction
+// using the given rules: `method`, `host`, `path`, `query`, `headers` and
+// `body`.
+func matchInteraction(rules []string, req *http.Request, body []byte, interaction *Interaction) (bool, error) {
+	recorded, err := url.Parse(interaction.Request.URL)
+	if err != nil {
+		return false, err
+	}
+
+	for _, rule := range rules {
+		switch strings.TrimSpace(rule) {
+		case "method":
+			if !strings.EqualFold(req.Method, interaction.Request.Method) {
+				return false, nil
+			}
+		case "host":
+			if req.URL.Host != recorded.Host {
+				return false, nil
+			}
+		case "path":
+			if req.URL.Path != recorded.Path {
+				return false, nil
+			}
+		case "query":
+			if !matchValues(req.URL.Query(), recorded.Query()) {
+				return false, nil
+			}
+		case "headers":
+			for name := range interaction.Request.Headers {
+				if !matchValues(url.Values{name: req.Header[name]}, url.Values{name: interaction.Request.Headers[name]}) {
+					return false, nil
+				}
+			}
+		case "body":
+			recordedBody, err := interaction.Request.bytes()
+			if err != nil {
+				return false, err
+			}
+
+			if !matchBody(body, recordedBody) {
+				return false, nil
+			}
+		case "":
+		default:
+			return false, fmt.Errorf("unknown replay match rule %s, expected one of: method, host, path, query, headers, body", rule)
+		}
+	}
+
+	return true, nil
+}
+
+// matchValues compares query params or headers. Recorded values that were
+// scrubbed match any value.
+func matchValues(actual, recorded map[string][]string) bool {
+	if len(actual) != len(recorded) {
+		return false
+	}
+
+	for name, values := range recorded {
+		if len(actual[name]) != len(values) {
+			return false
+		}
+
+		for i, value := range values {
+			if strings.Contains(value, hidden) {
+				continue
+			}
+
+			if actual[name][i] != value {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// matchBody compares request bodies, ignoring formatting differences when
+// both are JSON.
+func matchBody(actual, recorded []byte) bool {
+	if bytes.Equal(actual, recorded) {
+		return true
+	}
+
+	var a, r interface{}
+	if json.Unmarshal(actual, &a) != nil || json.Unmarshal(recorded, &r) != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(a, r)
+}
+
+// CassetteMiddleware records requests and responses to the cassette file
+// given by `record`, or replays them from the file given by `replay` without
+// using the network. Replayed requests are matched using the comma-separated
+// rules in `replay-match`. Must be registered after `RetryMiddleware` so only
+// the final response of each request is recorded.
+func CassetteMiddleware() {
+	var mu sync.Mutex
+	replays := map[string]*replayTransport{}
+
+	Client.UseHandler("before dial", func(ctx *context.Context, h context.Handler) {
+		if path := viper.GetString("replay"); path != "" {
+			match := viper.GetString("replay-match")
+			key := path + "\n" + match
+
+			mu.Lock()
+			transport := replays[key]
+			if transport == nil {
+				interactions, err := LoadCassette(path)
+				if err != nil {
+					mu.Unlock()
+					h.Error(ctx, err)
+					return
+				}
+
+				transport = &replayTransport{
+					interactions: interactions,
+					used:         map[*Interaction]bool{},
+					match:        strings.Split(match, ","),
+				}
+				replays[key] = transport
+			}
+			mu.Unlock()
+
+			ctx.Client.Transport = transport
+		} else if path := viper.GetString("record"); path != "" {
+			transport := ctx.Client.Transport
+			if transport == nil {
+				transport = http.DefaultTransport
+			}
+
+			ctx.Client.Transport = &recordTransport{
+				transport: transport,
+				path:      path,
+				secrets:   profileSecrets(),
+			}
+		}
+
+		h.Next(ctx)
+	})
+}
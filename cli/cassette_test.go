@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordReplay(t *testing.T) {
+	Init(&Config{
+		AppName: "test",
+	})
+
+	dir, err := ioutil.TempDir("", "cassette")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cassette.jsonl")
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=abc")
+		w.Write([]byte(`{"name": "` + r.URL.Query().Get("name") + `"}`))
+	}))
+
+	viper.Set("record", path)
+	resp, err := Client.Get().URL(server.URL+"/items?name=one&api_key=secret").AddHeader("Authorization", "Bearer secret").Do()
+	viper.Set("record", "")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"name": "one"}`, resp.String())
+	server.Close()
+
+	interactions, err := LoadCassette(path)
+	assert.NoError(t, err)
+	assert.Len(t, interactions, 1)
+	assert.Equal(t, "Bearer **HIDDEN**", interactions[0].Request.Headers.Get("Authorization"))
+	assert.Contains(t, interactions[0].Request.URL, "api_key=%2A%2AHIDDEN%2A%2A")
+	assert.Equal(t, "**HIDDEN**", interactions[0].Response.Headers.Get("Set-Cookie"))
+
+	// The server is gone, so these must be served from the cassette.
+	viper.Set("replay", path)
+	defer viper.Set("replay", "")
+
+	resp, err = Client.Get().URL(server.URL + "/items?name=one&api_key=other").Do()
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, `{"name": "one"}`, resp.String())
+	assert.Equal(t, 1, calls)
+
+	_, err = Client.Get().URL(server.URL + "/items?name=two&api_key=other").Do()
+	assert.Error(t, err)
+
+	viper.Set("replay-match", "method,path")
+	defer viper.Set("replay-match", "method,path,query,body")
+
+	_, err = Client.Get().URL(server.URL + "/items?name=two").Do()
+	assert.NoError(t, err)
+}
+
+func TestRecordStreaming(t *testing.T) {
+	dir := setupTest(t)
+	path := filepath.Join(dir, "cassette.jsonl")
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Write([]byte(`{"id": 1}` + "\n"))
+		w.(http.Flusher).Flush()
+
+		// Wait for the client to see the first item before sending the next.
+		select {
+		case <-release:
+		case <-time.After(5 * time.Second):
+		}
+		w.Write([]byte(`{"id": 2}` + "\n"))
+	}))
+	defer server.Close()
+
+	viper.Set("record", path)
+	resp, err := Client.Get().URL(server.URL).Do()
+	viper.Set("record", "")
+	assert.NoError(t, err)
+
+	start := time.Now()
+	items := 0
+	assert.NoError(t, StreamResponse(resp, func(item interface{}) error {
+		items++
+		if items == 1 {
+			close(release)
+		}
+		return nil
+	}))
+	assert.Equal(t, 2, items)
+	assert.True(t, time.Since(start) < 5*time.Second)
+
+	interactions, err := LoadCassette(path)
+	assert.NoError(t, err)
+	assert.Len(t, interactions, 1)
+	assert.Equal(t, `{"id": 1}`+"\n"+`{"id": 2}`+"\n", interactions[0].Response.Body)
+}
+
+func TestMatchBody(t *testing.T) {
+	assert.True(t, matchBody([]byte(`{"a": 1, "b": 2}`), []byte(`{"b":2,"a":1}`)))
+	assert.True(t, matchBody([]byte("plain"), []byte("plain")))
+	assert.False(t, matchBody([]byte(`{"a": 1}`), []byte(`{"a": 2}`)))
+}
@@ -3,7 +3,6 @@ package cli
 import (
 	"fmt"
 	"io"
-	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
@@ -57,6 +56,7 @@ type Config struct {
 func Init(config *Config) {
 	initConfig(config.AppName, config.EnvPrefix)
 	initCache(config.AppName)
+	initContext()
 	authInitialized = false
 
 	// Determine if we are using a TTY or colored output is forced-on.
@@ -81,7 +81,15 @@ func Init(config *Config) {
 
 	Client = gentleman.New()
 	UserAgentMiddleware()
+	ProfileMiddleware()
+	TimeoutMiddleware()
+	SignMiddleware()
 	LogMiddleware(tty)
+	TransportMiddleware()
+	RetryMiddleware()
+	HTTPCacheMiddleware()
+	CassetteMiddleware()
+	DryRunMiddleware(tty)
 
 	Formatter = NewDefaultFormatter(tty)
 
@@ -89,6 +97,8 @@ func Init(config *Config) {
 		Use:     filepath.Base(os.Args[0]),
 		Version: config.Version,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			applyProfile()
+
 			if viper.GetBool("verbose") {
 				zerolog.SetGlobalLevel(zerolog.DebugLevel)
 
@@ -97,13 +107,17 @@ func Init(config *Config) {
 				// Hide any secret values
 				for k := range settings {
 					if strings.Contains(k, "secret") || strings.Contains(k, "password") {
-						settings[k] = "**HIDDEN**"
+						settings[k] = hidden
 					}
 				}
 
 				log.Info().Fields(settings).Msg("Configuration")
 			}
 
+			if err := ValidateFormat(); err != nil {
+				return err
+			}
+
 			if PreRun != nil {
 				if err := PreRun(cmd, args); err != nil {
 					return err
@@ -128,11 +142,34 @@ func Init(config *Config) {
 		Run:   showHelpInput,
 	})
 
+	initProfileCommand()
+
 	AddGlobalFlag("verbose", "", "Enable verbose log output", false)
-	AddGlobalFlag("output-format", "o", "Output format [json, yaml]", "json")
+	AddGlobalFlag("output-format", "o", formatUsage(), "json")
+	AddGlobalFlag("columns", "", "Comma-separated JMESPath column expressions for table, csv and tsv output", "")
 	AddGlobalFlag("query", "q", "Filter / project results using JMESPath", "")
+	AddGlobalFlag("template", "", "Render output using a Go template, e.g. '{{.id}}: {{.name}}'", "")
+	AddGlobalFlag("template-file", "", "Render output using a Go template file", "")
 	AddGlobalFlag("raw", "", "Output result of query as raw rather than an escaped JSON string or list", false)
+	AddGlobalFlag("include", "i", "Include the response status and headers in the output", false)
+	AddGlobalFlag("full-response", "", "Output the response status, headers and body as one document, e.g. for use with --query", false)
 	AddGlobalFlag("server", "", "Override server URL", "")
+	AddGlobalFlag("profile", "", "Profile to use for credentials and settings", "default")
+	AddGlobalFlag("dry-run", "", "Print the request instead of sending it", false)
+	AddGlobalFlag("dry-run-format", "", "Dry run output format [http, curl]", "http")
+	AddGlobalFlag("show-secrets", "", "Show secrets like auth headers in dry run output", false)
+	AddGlobalFlag("no-cache", "", "Bypass the HTTP response cache", false)
+	AddGlobalFlag("record", "", "Record requests and responses to a cassette file", "")
+	AddGlobalFlag("replay", "", "Replay responses from a cassette file instead of sending requests", "")
+	AddGlobalFlag("ca-file", "", "Path to a PEM file with extra CA certificates to trust", "")
+	AddGlobalFlag("cert-file", "", "Path to a PEM client certificate for mutual TLS", "")
+	AddGlobalFlag("key-file", "", "Path to the PEM private key for the client certificate", "")
+	AddGlobalFlag("insecure", "", "Skip TLS certificate verification, e.g. for local development", false)
+	AddGlobalFlag("proxy", "", "Proxy URL, e.g. http://proxy:3128 (defaults to HTTP_PROXY/HTTPS_PROXY)", "")
+	AddGlobalFlag("timeout", "", "Request timeout, e.g. 30s (0 disables)", "0s")
+	AddGlobalFlag("retry", "", "Maximum number of retries for failed requests", 0)
+	AddGlobalFlag("retry-backoff", "", "Initial delay between retries, doubled after each attempt", "1s")
+	AddGlobalFlag("retry-status", "", "Comma-separated HTTP status codes to retry", "429,502,503,504")
 }
 
 func userHomeDir() string {
@@ -147,6 +184,8 @@ func userHomeDir() string {
 }
 
 func initConfig(appName, envPrefix string) {
+	envVarPrefix = envPrefix
+
 	// One-time setup to ensure the path exists so we can write files into it
 	// later as needed.
 	configDir := path.Join(userHomeDir(), "."+appName)
@@ -170,23 +209,20 @@ func initConfig(appName, envPrefix string) {
 	viper.Set("app-name", appName)
 	viper.Set("config-directory", configDir)
 	viper.SetDefault("server-index", 0)
+	viper.SetDefault("retry-max-delay", "30s")
+	viper.SetDefault("retry-jitter", 0.2)
+	viper.SetDefault("retry-all-methods", false)
+	viper.SetDefault("http-cache", false)
+	viper.SetDefault("tls-min-version", "")
+	viper.SetDefault("no-proxy", "")
+	viper.SetDefault("encryption-passphrase", "")
+	viper.SetDefault("encryption-key-file", "")
+	viper.SetDefault("replay-match", "method,path,query,body")
 }
 
 func initCache(appName string) {
-	Cache = viper.New()
-	Cache.SetConfigName("cache")
-	Cache.AddConfigPath("$HOME/." + appName + "/")
-
-	// Write a blank cache if no file is already there. Later you can use
-	// cli.Cache.SaveConfig() to write new values.
-	filename := path.Join(viper.GetString("config-directory"), "cache.json")
-	if _, err := os.Stat(filename); os.IsNotExist(err) {
-		if err := ioutil.WriteFile(filename, []byte("{}"), 0600); err != nil {
-			panic(err)
-		}
-	}
-
-	Cache.ReadInConfig()
+	// Later you can use `cli.SaveCache()` to write new values.
+	Cache = loadSettings("cache")
 }
 
 func showHelpConfig(cmd *cobra.Command, args []string) {
@@ -222,10 +258,19 @@ Configuration files can be used to configure the CLI and can be written using JS
 
 Some configuration values are not exposed as command options but can be set via prefixed environment variables or in configuration files. They are documented here.
 
-Name      | Type   | Description
---------- | ------ | -----------
-¬color¬   | ¬bool¬ | Force colorized output.
-¬nocolor¬ | ¬bool¬ | Disable colorized output.
+Name                    | Type     | Description
+----------------------- | -------- | -----------
+¬color¬                 | ¬bool¬   | Force colorized output.
+¬nocolor¬               | ¬bool¬   | Disable colorized output.
+¬retry-max-delay¬       | ¬string¬ | Maximum delay between retries, e.g. ¬30s¬.
+¬retry-jitter¬          | ¬float¬  | Fraction of the retry delay to randomly add or subtract.
+¬retry-all-methods¬     | ¬bool¬   | Also retry non-idempotent methods like ¬POST¬.
+¬http-cache¬            | ¬bool¬   | Cache ¬GET¬ responses on disk, honoring ¬Cache-Control¬ and revalidating with ¬ETag¬.
+¬tls-min-version¬       | ¬string¬ | Minimum TLS version: ¬1.0¬, ¬1.1¬, ¬1.2¬ or ¬1.3¬.
+¬no-proxy¬              | ¬string¬ | Comma-separated hosts, domains and CIDR ranges that bypass the proxy.
+¬encryption-passphrase¬ | ¬string¬ | Passphrase for encrypted credentials and cache, usually set via the environment.
+¬encryption-key-file¬   | ¬string¬ | File containing the passphrase for encrypted credentials and cache.
+¬replay-match¬          | ¬string¬ | Comma-separated rules to match replayed requests: ¬method¬, ¬host¬, ¬path¬, ¬query¬, ¬headers¬, ¬body¬.
 `
 
 	help = strings.Replace(help, "¬", "`", -1)
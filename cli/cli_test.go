@@ -2,6 +2,8 @@ package cli
 
 import (
 	"bytes"
+	"io/ioutil"
+	"os"
 	"strings"
 	"testing"
 
@@ -10,6 +12,42 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+// setupTest initializes the CLI with plain output and an empty temporary
+// config directory for the credentials and cache. The config directory and
+// output settings are restored once the test finishes. Returns the
+// directory.
+func setupTest(t *testing.T) string {
+	stdout, stderr, formatter, color := Stdout, Stderr, Formatter, tty
+
+	Init(&Config{
+		AppName: "test",
+	})
+	configDir := viper.GetString("config-directory")
+
+	dir, err := ioutil.TempDir("", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		os.RemoveAll(dir)
+		viper.Set("config-directory", configDir)
+		Stdout, Stderr, Formatter, tty = stdout, stderr, formatter, color
+
+		ReloadCache()
+		if Creds != nil {
+			reloadCreds()
+		}
+	})
+
+	viper.Set("config-directory", dir)
+	tty = false
+	Formatter = NewDefaultFormatter(false)
+	ReloadCache()
+
+	return dir
+}
+
 // execute a command against the configured CLI
 func execute(cmd string) string {
 	out := new(bytes.Buffer)
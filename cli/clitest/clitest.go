@@ -0,0 +1,44 @@
+// Package clitest provides utilities for testing packages which build on the
+// CLI, like auth handlers.
+package clitest
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/danielgtaylor/openapi-cli-generator/cli"
+	"github.com/spf13/viper"
+)
+
+// Setup initializes the CLI with plain output and an empty temporary config
+// directory for the credentials and cache. The config directory and output
+// settings are restored once the test finishes. Returns the directory.
+func Setup(t *testing.T) string {
+	stdout, stderr, formatter := cli.Stdout, cli.Stderr, cli.Formatter
+
+	cli.Init(&cli.Config{
+		AppName: "test",
+	})
+	configDir := viper.GetString("config-directory")
+
+	dir, err := ioutil.TempDir("", "clitest")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		os.RemoveAll(dir)
+		viper.Set("config-directory", configDir)
+		cli.Stdout, cli.Stderr, cli.Formatter = stdout, stderr, formatter
+
+		// Reload the credentials and cache from the restored directory.
+		cli.SetStore(cli.Store)
+	})
+
+	viper.Set("config-directory", dir)
+	cli.Formatter = cli.NewDefaultFormatter(false)
+	cli.ReloadCache()
+
+	return dir
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/rs/zerolog/log"
+	gentleman "gopkg.in/h2non/gentleman.v2"
+	gcontext "gopkg.in/h2non/gentleman.v2/context"
+)
+
+// Context is canceled when the CLI is interrupted, e.g. via Ctrl-C. Commands
+// pass it to operations so that in-flight requests and waiters are stopped.
+var Context context.Context = context.Background()
+
+var cancelContext context.CancelFunc = func() {}
+var interrupts chan os.Signal
+
+// initContext sets up a new cancelable context and installs the signal
+// handler. This is safe to call many times.
+func initContext() {
+	cancelContext()
+	Context, cancelContext = context.WithCancel(context.Background())
+
+	if interrupts != nil {
+		return
+	}
+
+	interrupts = make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		for sig := range interrupts {
+			if Context.Err() != nil {
+				// Interrupted again while something, e.g. reading from stdin,
+				// isn't watching the context. Exit like the default handler.
+				code := 1
+				if s, ok := sig.(syscall.Signal); ok {
+					code = 128 + int(s)
+				}
+				os.Exit(code)
+			}
+
+			log.Warn().Msg("Interrupted, canceling in-flight requests. Interrupt again to exit immediately.")
+			cancelContext()
+		}
+	}()
+}
+
+// WithContext associates a request with the given context, so that the
+// request is canceled along with it. The request's middleware context values
+// are preserved.
+func WithContext(ctx context.Context, req *gentleman.Request) *gentleman.Request {
+	store := req.Context.Request.Context().Value(gcontext.Key)
+	req.Context.Request = req.Context.Request.WithContext(context.WithValue(ctx, gcontext.Key, store))
+
+	return req
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// slowServer returns a server which only responds once the client gives up.
+func slowServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(10 * time.Second):
+		}
+	}))
+}
+
+func TestTimeout(t *testing.T) {
+	setupTest(t)
+
+	server := slowServer()
+	defer server.Close()
+
+	assert.NoError(t, Root.PersistentFlags().Set("timeout", "100ms"))
+
+	start := time.Now()
+	_, err := Client.Get().URL(server.URL).Do()
+	assert.Error(t, err)
+	assert.True(t, time.Since(start) < 5*time.Second)
+}
+
+func TestContextCancel(t *testing.T) {
+	setupTest(t)
+	defer initContext()
+
+	server := slowServer()
+	defer server.Close()
+
+	cancelSoon := func() {
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			cancelContext()
+		}()
+	}
+
+	// Waiting for a lock held elsewhere stops when canceled.
+	unlock, err := Lock("cache")
+	assert.NoError(t, err)
+
+	cancelSoon()
+	start := time.Now()
+	_, err = Lock("cache")
+	assert.Equal(t, context.Canceled, err)
+	assert.True(t, time.Since(start) < 5*time.Second)
+	unlock()
+
+	// In-flight requests are canceled too.
+	initContext()
+
+	cancelSoon()
+	start = time.Now()
+	_, err = WithContext(Context, Client.Get().URL(server.URL)).Do()
+	assert.Error(t, err)
+	assert.True(t, time.Since(start) < 5*time.Second)
+}
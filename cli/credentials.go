@@ -4,11 +4,11 @@ import (
 	"fmt"
 	"net/http"
 	"os"
-	"path"
 	"strings"
 
 	"github.com/olekukonko/tablewriter"
 	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"gopkg.in/h2non/gentleman.v2/context"
@@ -24,6 +24,44 @@ type AuthHandler interface {
 	OnRequest(log *zerolog.Logger, request *http.Request) error
 }
 
+// AuthResponseHandler is an optional interface for auth handlers which need
+// to react to responses. If it returns true, e.g. after clearing an access
+// token which the server rejected, then the `Authorization` header is removed
+// and the request is authorized and sent again. This happens at most once
+// per request.
+type AuthResponseHandler interface {
+	OnResponse(log *zerolog.Logger, request *http.Request, response *http.Response) (bool, error)
+}
+
+// AuthLoginHandler is an optional interface for auth handlers to customize
+// `auth login`. By default cached tokens are cleared and a new token is
+// fetched via `OnRequest`. It is called with the profile being logged into as
+// the current one.
+type AuthLoginHandler interface {
+	Login(log *zerolog.Logger) error
+}
+
+// AuthProfileValidator is an optional interface for auth handlers to check
+// the values of a new profile before it is saved by `auth add-profile`.
+type AuthProfileValidator interface {
+	ValidateProfile(profile map[string]string) error
+}
+
+// AuthLogoutHandler is an optional interface for auth handlers which need to
+// do more than clear cached tokens when logging out, e.g. revoke them. It is
+// called with the profile being logged out as the current one.
+type AuthLogoutHandler interface {
+	Logout(log *zerolog.Logger) error
+}
+
+// AuthSigner is an optional interface for auth handlers which sign requests,
+// e.g. with an HMAC of the request contents. Sign is called after `OnRequest`
+// once the method, URL, headers and body are final. The body may be read as
+// long as it is replaced.
+type AuthSigner interface {
+	Sign(log *zerolog.Logger, request *http.Request) error
+}
+
 // AuthHandlers is the map of registered auth type names to handlers
 var AuthHandlers = make(map[string]AuthHandler)
 
@@ -42,6 +80,8 @@ func initAuth() {
 	// Set up the credentials file
 	InitCredentialsFile()
 
+	AddGlobalFlag("no-browser", "", "Print the login URL instead of opening a browser", false)
+
 	// Add base auth commands
 	authCommand = &cobra.Command{
 		Use:   "auth",
@@ -93,7 +133,10 @@ func initAuth() {
 
 						row := []string{name}
 						for _, key := range listKeys {
-							row = append(row, profile[strings.Replace(key, "-", "_", -1)].(string))
+							// Values may be missing, e.g. when they are loaded via
+							// a credential process.
+							value, _ := profile[strings.Replace(key, "-", "_", -1)].(string)
+							row = append(row, value)
 						}
 						table.Append(row)
 					}
@@ -105,9 +148,17 @@ func initAuth() {
 		},
 	})
 
+	initAuthProfileCommands()
+	initTokenCommand()
+	initEncryptionCommands()
+
 	// Install auth middleware
 	Client.UseRequest(func(ctx *context.Context, h context.Handler) {
-		profile := GetProfile()
+		profile, err := LoadProfile()
+		if err != nil {
+			h.Error(ctx, err)
+			return
+		}
 
 		handler := AuthHandlers[profile["type"]]
 		if handler == nil {
@@ -120,6 +171,45 @@ func initAuth() {
 			return
 		}
 
+		ctx.Set("auth-handler", handler)
+
+		h.Next(ctx)
+	})
+
+	Client.UseHandler("before dial", func(ctx *context.Context, h context.Handler) {
+		handler, _ := ctx.Get("auth-handler").(AuthHandler)
+		log := ctx.Get("log").(*zerolog.Logger)
+
+		if _, ok := handler.(AuthResponseHandler); ok {
+			transport := ctx.Client.Transport
+			if transport == nil {
+				transport = http.DefaultTransport
+			}
+
+			ctx.Client.Transport = &authRetryTransport{
+				transport: transport,
+				log:       log,
+				handler:   handler,
+			}
+		}
+
+		h.Next(ctx)
+	})
+}
+
+// SignMiddleware lets auth handlers implementing `AuthSigner` sign requests.
+// Signing must wait until the URL, headers and body are final, which isn't
+// the case until just before the request is sent. Must be registered before
+// `LogMiddleware` and `DryRunMiddleware` so they show the signed request.
+func SignMiddleware() {
+	Client.UseHandler("before dial", func(ctx *context.Context, h context.Handler) {
+		if signer, ok := ctx.Get("auth-handler").(AuthSigner); ok {
+			if err := signer.Sign(ctx.Get("log").(*zerolog.Logger), ctx.Request); err != nil {
+				h.Error(ctx, err)
+				return
+			}
+		}
+
 		h.Next(ctx)
 	})
 }
@@ -137,49 +227,121 @@ func UseAuth(typeName string, handler AuthHandler) {
 	// Set up the add-profile command.
 	keys := handler.ProfileKeys()
 
-	use := " [flags] <name>"
-	for _, name := range keys {
-		use += " <" + strings.Replace(name, "_", "-", -1) + ">"
+	use := " [flags] <name> ["
+	for i, name := range keys {
+		if i > 0 {
+			use += " "
+		}
+		use += "<" + strings.Replace(name, "_", "-", -1) + ">"
 	}
+	use += "]"
+
+	run := func(cmd *cobra.Command, args []string) error {
+		// Missing values can be passed as flags or are read from stdin, which
+		// prompts for them when run interactively. This keeps secrets out of the
+		// shell history.
+		p := newPrompter()
+
+		if len(args) == 0 {
+			value, err := p.Prompt("Profile name", false)
+			if err != nil {
+				return err
+			}
+			args = append(args, value)
+		}
 
-	run := func(cmd *cobra.Command, args []string) {
+		// Replace periods in the name since Viper will create nested structures
+		// in the config and this isn't what we want!
 		name := strings.Replace(args[0], ".", "-", -1)
+		if name == "" {
+			return fmt.Errorf("profile name is required")
+		}
+
 		Creds.Set("profiles."+name+".type", typeName)
 
+		process, _ := cmd.Flags().GetString("credential-process")
+		if process != "" {
+			Creds.Set("profiles."+name+".credential_process", process)
+		}
+
 		for i, key := range keys {
-			// Replace periods in the name since Viper will create nested structures
-			// in the config and this isn't what we want!
-			Creds.Set("profiles."+name+"."+strings.Replace(key, "-", "_", -1), args[i+1])
+			var value string
+			if i+1 < len(args) {
+				value = args[i+1]
+			} else if flag := cmd.Flags().Lookup(strings.Replace(key, "_", "-", -1)); flag != nil && flag.Changed {
+				value = flag.Value.String()
+			} else if process != "" {
+				// The value is provided by the credential process at request time.
+				continue
+			} else {
+				v, err := p.Prompt(promptLabel(key), isSensitive(key))
+				if err != nil {
+					reloadCreds()
+					return err
+				}
+				value = v
+			}
+
+			Creds.Set("profiles."+name+"."+strings.Replace(key, "-", "_", -1), value)
 		}
 
-		filename := path.Join(viper.GetString("config-directory"), "credentials.json")
-		if err := Creds.WriteConfigAs(filename); err != nil {
-			panic(err)
+		if validator, ok := handler.(AuthProfileValidator); ok {
+			if err := validator.ValidateProfile(Creds.GetStringMapString("profiles." + name)); err != nil {
+				// Discard the new profile values.
+				reloadCreds()
+				return fmt.Errorf("invalid profile: %v", err)
+			}
 		}
+
+		if validate, _ := cmd.Flags().GetBool("validate"); validate {
+			previous := viper.GetString("profile")
+			viper.Set("profile", name)
+			err := loginProfile(name, handler)
+			viper.Set("profile", previous)
+
+			if err != nil {
+				// Discard the new profile values.
+				reloadCreds()
+				return fmt.Errorf("profile validation failed: %v", err)
+			}
+		}
+
+		return Creds.Save()
 	}
 
+	var cmd *cobra.Command
 	if typeName == "" {
 		// Backward-compatibility use-case without an explicit type. Set up the
 		// `add-profile` command as the only way to authenticate.
-		if authAddCommand.Run != nil {
+		if authAddCommand.RunE != nil {
 			// This fallback code path was already used, so we must be registering
 			// a *second* anonymous auth type, which is not allowed.
 			panic("register auth type names to use multi-auth")
 		}
 
-		authAddCommand.Use = "add-profile" + use
-		authAddCommand.Short = "Add a new named authentication profile"
-		authAddCommand.Args = cobra.ExactArgs(1 + len(keys))
-		authAddCommand.Run = run
+		cmd = authAddCommand
+		cmd.Use = "add-profile" + use
+		cmd.Short = "Add a new named authentication profile"
 	} else {
 		// Add a new type-specific `add-profile` subcommand.
-		authAddCommand.AddCommand(&cobra.Command{
+		cmd = &cobra.Command{
 			Use:   typeName + use,
 			Short: "Add a new named " + typeName + " authentication profile",
-			Args:  cobra.ExactArgs(1 + len(keys)),
-			Run:   run,
-		})
+		}
+		authAddCommand.AddCommand(cmd)
+	}
+
+	cmd.Args = cobra.MaximumNArgs(1 + len(keys))
+	cmd.RunE = run
+
+	for _, key := range keys {
+		name := strings.Replace(key, "_", "-", -1)
+		if Root.PersistentFlags().Lookup(name) == nil {
+			cmd.Flags().String(name, "", "Value for "+name)
+		}
 	}
+	cmd.Flags().String("credential-process", "", "Command whose JSON output provides profile values at request time")
+	cmd.Flags().Bool("validate", false, "Fetch a token to check the profile before saving it")
 }
 
 // CredentialsFile holds credential-related information.
@@ -193,9 +355,16 @@ type CredentialsFile struct {
 // Use this only after `InitCredentials` has been called.
 var Creds *CredentialsFile
 
-// GetProfile returns the current profile's configuration.
+// GetProfile returns the current profile's configuration, including values
+// from its `credential_process` if set. Use `LoadProfile` to handle errors
+// from the credential process.
 func GetProfile() map[string]string {
-	return Creds.GetStringMapString("profiles." + strings.Replace(viper.GetString("profile"), ".", "-", -1))
+	profile, err := LoadProfile()
+	if err != nil {
+		log.Error().Err(err).Str("profile", profileName()).Msg("Could not load profile")
+	}
+
+	return profile
 }
 
 // ProfileKeys lets you specify authentication profile keys to be used in
@@ -222,14 +391,12 @@ func ProfileListKeys(keys ...string) func(*CredentialsFile) error {
 func InitCredentialsFile() {
 	// Setup a credentials file, kept separate from configuration which might
 	// get checked into source control.
-	Creds = &CredentialsFile{viper.New(), []string{}, []string{}}
-
-	Creds.SetConfigName("credentials")
-	Creds.AddConfigPath("$HOME/." + viper.GetString("app-name") + "/")
-	Creds.ReadInConfig()
+	Creds = &CredentialsFile{loadSettings("credentials"), []string{}, []string{}}
 
-	// Register a new `--profile` flag.
-	AddGlobalFlag("profile", "", "Credentials profile to use for authentication", "default")
+	// Register a new `--profile` flag, unless `cli.Init` already did.
+	if Root.PersistentFlags().Lookup("profile") == nil {
+		AddGlobalFlag("profile", "", "Credentials profile to use for authentication", "default")
+	}
 }
 
 // InitCredentials sets up the profile/auth commands. Must be called *after* you
@@ -270,8 +437,7 @@ func InitCredentials(options ...func(*CredentialsFile) error) {
 				Creds.Set("profiles."+name+"."+strings.Replace(key, "-", "_", -1), args[i+1])
 			}
 
-			filename := path.Join(viper.GetString("config-directory"), "credentials.json")
-			if err := Creds.WriteConfigAs(filename); err != nil {
+			if err := Creds.Save(); err != nil {
 				panic(err)
 			}
 		},
@@ -0,0 +1,208 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gopkg.in/h2non/gentleman.v2/context"
+)
+
+// hidden replaces secret values in output, matching the verbose config log.
+const hidden = "**HIDDEN**"
+
+// sensitiveHeaders always contain credentials.
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// credentialNames are header and query param names which carry credentials.
+// Names are lowercase with dashes replaced by underscores.
+var credentialNames = map[string]bool{
+	"access_token":         true,
+	"api_key":              true,
+	"apikey":               true,
+	"client_secret":        true,
+	"id_token":             true,
+	"key":                  true,
+	"password":             true,
+	"refresh_token":        true,
+	"token":                true,
+	"x_api_key":            true,
+	"x_amz_security_token": true,
+	"x_auth_token":         true,
+}
+
+// isCredential returns whether a header or query param carries credentials.
+func isCredential(name string) bool {
+	return credentialNames[strings.Replace(strings.ToLower(name), "-", "_", -1)]
+}
+
+// isSensitive returns whether a profile key name likely refers to a secret
+// value, e.g. to hide it when prompting or listing.
+func isSensitive(name string) bool {
+	name = strings.ToLower(name)
+	for _, s := range []string{"secret", "password", "token", "key"} {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// profileSecrets returns the secret values stored in the current profile, if
+// auth has been set up.
+func profileSecrets() []string {
+	secrets := make([]string, 0)
+
+	if Creds == nil {
+		return secrets
+	}
+
+	for k, v := range GetProfile() {
+		if v != "" && isSensitive(k) {
+			secrets = append(secrets, v)
+		}
+	}
+
+	return secrets
+}
+
+// redactValue hides the value of a header or query param if it carries
+// credentials, or if it contains one of the given secrets.
+func redactValue(name, value string, secrets []string) string {
+	if canonical := http.CanonicalHeaderKey(name); sensitiveHeaders[canonical] {
+		// Keep the auth scheme, e.g. `Bearer`, as it is useful to debug.
+		if parts := strings.SplitN(value, " ", 2); len(parts) == 2 && strings.HasSuffix(canonical, "Authorization") {
+			return parts[0] + " " + hidden
+		}
+		return hidden
+	}
+
+	if isCredential(name) {
+		return hidden
+	}
+
+	for _, secret := range secrets {
+		value = strings.Replace(value, secret, hidden, -1)
+	}
+
+	return value
+}
+
+// redactHeaders returns a copy of the headers with secret values hidden.
+func redactHeaders(header http.Header, secrets []string) http.Header {
+	redacted := make(http.Header, len(header))
+	for name, values := range header {
+		for _, value := range values {
+			redacted.Add(name, redactValue(name, value, secrets))
+		}
+	}
+
+	return redacted
+}
+
+// redactURL returns a copy of the URL with secret query param values hidden.
+func redactURL(original *url.URL, secrets []string) *url.URL {
+	u := *original
+	query := u.Query()
+	for name, values := range query {
+		for i, value := range values {
+			values[i] = redactValue(name, value, secrets)
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	return &u
+}
+
+// redact returns a copy of the request with secret header and query values
+// replaced. The original request is not modified.
+func redact(req *http.Request) *http.Request {
+	secrets := profileSecrets()
+
+	copied := *req
+	copied.Header = redactHeaders(req.Header, secrets)
+	copied.URL = redactURL(req.URL, secrets)
+
+	return &copied
+}
+
+// shellQuote quotes a string for use as a single POSIX shell argument.
+func shellQuote(value string) string {
+	return "'" + strings.Replace(value, "'", `'"'"'`, -1) + "'"
+}
+
+// renderCurl renders the request as a copy-pasteable `curl` command. The body
+// is expected in the format returned by `getBody`.
+func renderCurl(req *http.Request, body string) string {
+	parts := []string{"curl", "-X", req.Method, shellQuote(req.URL.String())}
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, value := range req.Header[name] {
+			parts = append(parts, "\\\n  -H", shellQuote(name+": "+value))
+		}
+	}
+
+	if body != "" {
+		parts = append(parts, "\\\n  --data-binary", shellQuote(strings.TrimSuffix(strings.TrimPrefix(body, "\n"), "\n")))
+	}
+
+	return strings.Join(parts, " ") + "\n"
+}
+
+// DryRunMiddleware prints requests instead of sending them when `dry-run` is
+// set. Requests are printed as raw HTTP or as a `curl` command based on
+// `dry-run-format`, after all other request middleware like auth has run.
+// Credential headers and query params as well as secrets from the profile are
+// redacted unless `show-secrets` is set. The body is printed as-is. Must be
+// registered after `LogMiddleware` so the request body is available.
+func DryRunMiddleware(useColor bool) {
+	Client.UseHandler("before dial", func(ctx *context.Context, h context.Handler) {
+		if !viper.GetBool("dry-run") {
+			h.Next(ctx)
+			return
+		}
+
+		req := ctx.Request
+		if !viper.GetBool("show-secrets") {
+			req = redact(req)
+		}
+
+		body := ctx.GetString("request-body")
+
+		var out string
+		switch viper.GetString("dry-run-format") {
+		case "curl":
+			out = renderCurl(req, body)
+		case "http":
+			rendered, err := renderRequest(req, body, useColor)
+			if err != nil {
+				h.Error(ctx, err)
+				return
+			}
+			out = rendered
+		default:
+			h.Error(ctx, fmt.Errorf("unknown dry run format %s, expected one of: http, curl", viper.GetString("dry-run-format")))
+			return
+		}
+
+		fmt.Fprint(Stdout, out)
+
+		// Stop here so that the request is never sent.
+		h.Stop(ctx)
+	})
+}
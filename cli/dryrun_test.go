@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedact(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com/items?api_key=abc&limit=5&sort_key=name", nil)
+	req.Header.Set("Authorization", "Bearer abc123")
+	req.Header.Set("X-Api-Key", "abc")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Idempotency-Key", "abc")
+
+	redacted := redact(req)
+
+	assert.Equal(t, "Bearer **HIDDEN**", redacted.Header.Get("Authorization"))
+	assert.Equal(t, "**HIDDEN**", redacted.Header.Get("X-Api-Key"))
+	assert.Equal(t, "application/json", redacted.Header.Get("Accept"))
+	assert.Equal(t, "abc", redacted.Header.Get("Idempotency-Key"))
+	assert.Equal(t, "**HIDDEN**", redacted.URL.Query().Get("api_key"))
+	assert.Equal(t, "5", redacted.URL.Query().Get("limit"))
+	assert.Equal(t, "name", redacted.URL.Query().Get("sort_key"))
+
+	// The original request must not be modified.
+	assert.Equal(t, "Bearer abc123", req.Header.Get("Authorization"))
+	assert.Equal(t, "abc", req.URL.Query().Get("api_key"))
+}
+
+func TestRenderCurl(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPost, "https://example.com/items", nil)
+	req.Header.Set("Content-Type", "application/json")
+
+	out := renderCurl(req, "\n{\"name\": \"it's\"}\n")
+	assert.Equal(t, "curl -X POST 'https://example.com/items' \\\n  -H 'Content-Type: application/json' \\\n  --data-binary '{\"name\": \"it'\"'\"'s\"}'\n", out)
+}
+
+func TestDryRun(t *testing.T) {
+	setupTest(t)
+
+	out := &bytes.Buffer{}
+	Stdout = out
+
+	viper.Set("dry-run", true)
+	viper.Set("dry-run-format", "curl")
+	defer viper.Set("dry-run", false)
+	defer viper.Set("dry-run-format", "http")
+
+	// The host does not exist, so this would fail if actually sent.
+	resp, err := Client.Get().URL("http://invalid.invalid/items").AddHeader("Authorization", "Bearer abc").Do()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, resp.StatusCode)
+	assert.Contains(t, out.String(), "curl -X GET 'http://invalid.invalid/items'")
+	assert.Contains(t, out.String(), "'Authorization: Bearer **HIDDEN**'")
+}
+
+// signingAuth signs requests with a header computed from the final body.
+type signingAuth struct{}
+
+func (a *signingAuth) ProfileKeys() []string {
+	return []string{}
+}
+
+func (a *signingAuth) OnRequest(log *zerolog.Logger, request *http.Request) error {
+	return nil
+}
+
+func (a *signingAuth) Sign(log *zerolog.Logger, request *http.Request) error {
+	body, _ := ioutil.ReadAll(request.Body)
+	request.Body = ioutil.NopCloser(bytes.NewReader(body))
+	request.Header.Set("Signature", fmt.Sprintf("%x", sha256.Sum256(body)))
+	return nil
+}
+
+func TestDryRunSigned(t *testing.T) {
+	setupTest(t)
+	UseAuth("", &signingAuth{})
+
+	out := &bytes.Buffer{}
+	Stdout = out
+
+	viper.Set("dry-run", true)
+	viper.Set("dry-run-format", "curl")
+	defer viper.Set("dry-run", false)
+	defer viper.Set("dry-run-format", "http")
+
+	_, err := Client.Post().URL("http://invalid.invalid/items").JSON(map[string]string{"hello": "world"}).Do()
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "-H 'Signature: ")
+	assert.Contains(t, out.String(), "hello")
+}
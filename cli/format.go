@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
+)
+
+// Encoder converts response data into an output format. Encoders may also
+// implement `Lexer() string` to return the name of a Chroma lexer, which is
+// used to colorize the output when writing to a terminal.
+type Encoder interface {
+	Encode(data interface{}) ([]byte, error)
+}
+
+// EncoderFunc is an adapter to allow the use of ordinary functions as
+// encoders.
+type EncoderFunc func(data interface{}) ([]byte, error)
+
+// Encode calls f(data).
+func (f EncoderFunc) Encode(data interface{}) ([]byte, error) {
+	return f(data)
+}
+
+// highlightedEncoder wraps an encoder function with a Chroma lexer name.
+type highlightedEncoder struct {
+	EncoderFunc
+	lexer string
+}
+
+// Lexer returns the name of the Chroma lexer used for highlighting.
+func (e highlightedEncoder) Lexer() string {
+	return e.lexer
+}
+
+var formatRegistry = map[string]Encoder{
+	"json": highlightedEncoder{func(data interface{}) ([]byte, error) {
+		return json.MarshalIndent(data, "", "  ")
+	}, "json"},
+	"yaml": highlightedEncoder{func(data interface{}) ([]byte, error) {
+		return yaml.Marshal(data)
+	}, "yaml"},
+	"ndjson": highlightedEncoder{encodeNDJSON, "json"},
+	"table":  tabularEncoder("table"),
+	"csv":    tabularEncoder("csv"),
+	"tsv":    tabularEncoder("tsv"),
+}
+
+// RegisterFormat registers a named output format, which can then be selected
+// via `--output-format`. Registering an existing name replaces that format.
+func RegisterFormat(name string, encoder Encoder) {
+	formatRegistry[name] = encoder
+
+	if Root != nil {
+		if flag := Root.PersistentFlags().Lookup("output-format"); flag != nil {
+			flag.Usage = formatUsage()
+		}
+	}
+}
+
+// Formats returns the sorted names of all registered output formats.
+func Formats() []string {
+	names := make([]string, 0, len(formatRegistry))
+	for name := range formatRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// formatUsage returns the `output-format` flag description.
+func formatUsage() string {
+	return "Output format [" + strings.Join(Formats(), ", ") + "]"
+}
+
+// ValidateFormat returns an error if the configured output format has not
+// been registered.
+func ValidateFormat() error {
+	format := viper.GetString("output-format")
+	if _, ok := formatRegistry[format]; !ok {
+		return fmt.Errorf("unknown output format %s, expected one of: %s", format, strings.Join(Formats(), ", "))
+	}
+
+	return nil
+}
+
+// encodeNDJSON encodes one compact JSON document per line, with each list
+// item on its own line.
+func encodeNDJSON(data interface{}) ([]byte, error) {
+	items, ok := data.([]interface{})
+	if !ok {
+		items = []interface{}{data}
+	}
+
+	var encoded []byte
+	for _, item := range items {
+		line, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+
+		encoded = append(encoded, line...)
+		encoded = append(encoded, '\n')
+	}
+
+	return encoded, nil
+}
+
+// tabularEncoder returns an encoder for a table, CSV or TSV using the
+// configured `columns`.
+func tabularEncoder(format string) EncoderFunc {
+	return func(data interface{}) ([]byte, error) {
+		return encodeTabular(format, data, configuredColumns())
+	}
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterFormat(t *testing.T) {
+	setupTest(t)
+
+	RegisterFormat("test", EncoderFunc(func(data interface{}) ([]byte, error) {
+		return []byte("custom"), nil
+	}))
+	defer delete(formatRegistry, "test")
+
+	assert.Contains(t, Formats(), "test")
+	assert.Contains(t, Root.PersistentFlags().Lookup("output-format").Usage, "test")
+
+	out := &bytes.Buffer{}
+	Stdout = out
+
+	viper.Set("output-format", "test")
+	defer viper.Set("output-format", "json")
+
+	assert.NoError(t, ValidateFormat())
+	assert.NoError(t, NewDefaultFormatter(false).Format(map[string]interface{}{}))
+	assert.Equal(t, "custom\n", out.String())
+
+	viper.Set("output-format", "invalid")
+	assert.Error(t, ValidateFormat())
+	assert.Error(t, NewDefaultFormatter(false).Format(map[string]interface{}{}))
+}
+
+func TestFormatQueryNoMatch(t *testing.T) {
+	setupTest(t)
+
+	out := &bytes.Buffer{}
+	Stdout = out
+
+	viper.Set("output-format", "json")
+	assert.NoError(t, Root.PersistentFlags().Set("query", "missing"))
+
+	assert.NoError(t, NewDefaultFormatter(false).Format(map[string]interface{}{"a": 1}))
+	assert.Equal(t, "null\n", out.String())
+}
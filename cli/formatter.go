@@ -1,7 +1,6 @@
 package cli
 
 import (
-	"encoding/json"
 	"fmt"
 	"reflect"
 
@@ -10,7 +9,6 @@ import (
 	"github.com/alecthomas/chroma/styles"
 	jmespath "github.com/danielgtaylor/go-jmespath-plus"
 	"github.com/spf13/viper"
-	"gopkg.in/yaml.v2"
 )
 
 func init() {
@@ -45,9 +43,13 @@ type ResponseFormatter interface {
 }
 
 // DefaultFormatter can apply JMESPath queries and can output prettyfied JSON
-// and YAML output. If Stdout is a TTY, then colorized output is provided. The
-// default formatter uses the `query` and `output-format` configuration
-// values to perform JMESPath queries and set JSON (default) or YAML output.
+// and YAML output, newline-delimited JSON, or flatten lists into a table, CSV
+// or TSV. Additional formats can be added via `RegisterFormat`. It can also
+// render a Go `text/template` via the `template` or `template-file`
+// configuration values. If Stdout is a TTY, then colorized output is
+// provided. The default formatter uses the `query`, `output-format` and
+// `columns` configuration values to perform JMESPath queries and set the
+// output format.
 type DefaultFormatter struct {
 	tty bool
 }
@@ -76,13 +78,30 @@ func (f *DefaultFormatter) Format(data interface{}) error {
 		data = result
 	}
 
+	if text, err := outputTemplate(); err != nil {
+		return err
+	} else if text != "" {
+		// Templates take precedence over the output format.
+		encoded, err := renderTemplate(text, data, f.tty)
+		if err != nil {
+			return err
+		}
+
+		_, err = Stdout.Write(encoded)
+		return err
+	}
+
 	// Encode to the requested output format using nice formatting.
 	var encoded []byte
 	var err error
 	var lexer string
 
 	handled := false
-	kind := reflect.TypeOf(data).Kind()
+	// Data is nil when nothing matched the query, which is output as `null`.
+	kind := reflect.Invalid
+	if data != nil {
+		kind = reflect.TypeOf(data).Kind()
+	}
 	if viper.GetBool("raw") && kind == reflect.String {
 		handled = true
 		dStr := data.(string)
@@ -118,22 +137,18 @@ func (f *DefaultFormatter) Format(data interface{}) error {
 	}
 
 	if !handled {
-		if viper.GetString("output-format") == "yaml" {
-			encoded, err = yaml.Marshal(data)
-
-			if err != nil {
-				return err
-			}
-
-			lexer = "yaml"
-		} else {
-			encoded, err = json.MarshalIndent(data, "", "  ")
+		encoder, ok := formatRegistry[viper.GetString("output-format")]
+		if !ok {
+			return ValidateFormat()
+		}
 
-			if err != nil {
-				return err
-			}
+		encoded, err = encoder.Encode(data)
+		if err != nil {
+			return err
+		}
 
-			lexer = "json"
+		if l, ok := encoder.(interface{ Lexer() string }); ok {
+			lexer = l.Lexer()
 		}
 	}
 
@@ -143,13 +158,13 @@ func (f *DefaultFormatter) Format(data interface{}) error {
 		encoded = append(encoded, '\n')
 	}
 
-	// Only colorize if we are a TTY.
-	if f.tty {
+	// Only colorize if we are a TTY and the format supports highlighting.
+	if f.tty && lexer != "" {
 		if err = quick.Highlight(Stdout, string(encoded), lexer, "terminal256", "cli-dark"); err != nil {
 			return err
 		}
 	} else {
-		fmt.Fprintln(Stdout, string(encoded))
+		fmt.Fprint(Stdout, string(encoded))
 	}
 
 	return nil
@@ -54,6 +54,30 @@ func getBody(r io.ReadCloser) (string, io.ReadCloser, error) {
 	return body, newReader, nil
 }
 
+// readBody reads and replaces a body so that it can be read again later.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+
+	data, err := ioutil.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+
+	*body = ioutil.NopCloser(bytes.NewReader(data))
+
+	return data, nil
+}
+
+// drainBody reads the rest of a response body and closes it so that the
+// connection can be reused.
+func drainBody(body io.ReadCloser) {
+	io.Copy(ioutil.Discard, body)
+	body.Close()
+}
+
 // UserAgentMiddleware sets the user-agent header on requests.
 func UserAgentMiddleware() {
 	Client.UseRequest(func(ctx *context.Context, h context.Handler) {
@@ -62,6 +86,42 @@ func UserAgentMiddleware() {
 	})
 }
 
+// TimeoutMiddleware limits the total time a request may take, including
+// retries and reading the response body, using the `timeout` setting.
+func TimeoutMiddleware() {
+	Client.UseRequest(func(ctx *context.Context, h context.Handler) {
+		if timeout := viper.GetDuration("timeout"); timeout > 0 {
+			ctx.Client.Timeout = timeout
+		}
+		h.Next(ctx)
+	})
+}
+
+// renderRequest renders the request as raw HTTP, optionally highlighted for
+// terminal output. The body is expected in the format returned by `getBody`.
+func renderRequest(req *http.Request, body string, useColor bool) (string, error) {
+	headers := ""
+	for key, val := range req.Header {
+		headers += key + ": " + val[0] + "\n"
+	}
+
+	if body != "" {
+		body = "\n" + body
+	}
+
+	raw := fmt.Sprintf("%s %s %s\n%s%s", req.Method, req.URL, req.Proto, headers, body)
+
+	if useColor {
+		sb := strings.Builder{}
+		if err := quick.Highlight(&sb, raw, "http", "terminal256", "cli-dark"); err != nil {
+			return raw, err
+		}
+		raw = sb.String()
+	}
+
+	return raw, nil
+}
+
 // LogMiddleware adds verbose log info to HTTP requests.
 func LogMiddleware(useColor bool) {
 	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
@@ -89,23 +149,9 @@ func LogMiddleware(useColor bool) {
 		ctx.Request.Body = newReader
 
 		if viper.GetBool("verbose") {
-			headers := ""
-			for key, val := range ctx.Request.Header {
-				headers += key + ": " + val[0] + "\n"
-			}
-
-			if body != "" {
-				body = "\n" + body
-			}
-
-			http := fmt.Sprintf("%s %s %s\n%s%s", ctx.Request.Method, ctx.Request.URL, ctx.Request.Proto, headers, body)
-
-			if useColor {
-				sb := strings.Builder{}
-				if err := quick.Highlight(&sb, http, "http", "terminal256", "cli-dark"); err != nil {
-					h.Error(ctx, err)
-				}
-				http = sb.String()
+			http, err := renderRequest(ctx.Request, body, useColor)
+			if err != nil {
+				h.Error(ctx, err)
 			}
 
 			log.Debug().Msgf("Making request:\n%s", indent(http))
@@ -123,12 +169,19 @@ func LogMiddleware(useColor bool) {
 				headers += key + ": " + val[0] + "\n"
 			}
 
-			body, newReader, err := getBody(ctx.Response.Body)
-			if err != nil {
-				h.Error(ctx, err)
-				return
+			body := "\n(streaming body)\n"
+			if !isStreamingType(ctx.Response.Header) {
+				// Reading a streaming body would block until the stream ends, so only
+				// read and log regular bodies.
+				var newReader io.ReadCloser
+				var err error
+				body, newReader, err = getBody(ctx.Response.Body)
+				if err != nil {
+					h.Error(ctx, err)
+					return
+				}
+				ctx.Response.Body = newReader
 			}
-			ctx.Response.Body = newReader
 
 			http := fmt.Sprintf("%s %s\n%s\n%s", ctx.Response.Proto, ctx.Response.Status, headers, body)
 
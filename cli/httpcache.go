@@ -0,0 +1,264 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"gopkg.in/h2non/gentleman.v2/context"
+)
+
+// cacheEntry is a cached response stored on disk.
+type cacheEntry struct {
+	URL      string           `json:"url"`
+	Stored   time.Time        `json:"stored"`
+	Response CassetteResponse `json:"response"`
+}
+
+// cacheControl parses a `Cache-Control` header into a map of directives to
+// their (possibly empty) values.
+func cacheControl(header http.Header) map[string]string {
+	directives := make(map[string]string)
+
+	for _, value := range header["Cache-Control"] {
+		for _, part := range strings.Split(value, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+
+			kv := strings.SplitN(part, "=", 2)
+			name := strings.ToLower(strings.TrimSpace(kv[0]))
+			if len(kv) == 2 {
+				directives[name] = strings.Trim(strings.TrimSpace(kv[1]), `"`)
+			} else {
+				directives[name] = ""
+			}
+		}
+	}
+
+	return directives
+}
+
+// freshness returns how long a response stays fresh after it was stored,
+// based on the `Cache-Control` max age or the `Expires` header.
+func freshness(header http.Header, stored time.Time) time.Duration {
+	directives := cacheControl(header)
+
+	if _, ok := directives["no-cache"]; ok {
+		return 0
+	}
+
+	var lifetime time.Duration
+	if maxAge, ok := directives["max-age"]; ok {
+		seconds, err := strconv.Atoi(maxAge)
+		if err != nil {
+			return 0
+		}
+		lifetime = time.Duration(seconds) * time.Second
+	} else if expires := header.Get("Expires"); expires != "" {
+		date, err := http.ParseTime(expires)
+		if err != nil {
+			// Invalid dates like `0` mean already expired.
+			return 0
+		}
+
+		base := stored
+		if d, err := http.ParseTime(header.Get("Date")); err == nil {
+			base = d
+		}
+		lifetime = date.Sub(base)
+	}
+
+	// Time the response already spent in shared caches counts against it.
+	if age, err := strconv.Atoi(header.Get("Age")); err == nil {
+		lifetime -= time.Duration(age) * time.Second
+	}
+
+	return lifetime
+}
+
+// cacheable returns whether a response may be stored. Only responses which
+// are either fresh for a while or can be revalidated are worth storing.
+func cacheable(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusOK || isStreamingType(resp.Header) {
+		return false
+	}
+
+	if _, ok := cacheControl(resp.Header)["no-store"]; ok {
+		return false
+	}
+
+	return resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != "" || freshness(resp.Header, time.Now()) > 0
+}
+
+// cacheTransport wraps an HTTP round tripper and stores responses to `GET`
+// requests on disk, serving them while fresh and revalidating them once
+// stale.
+type cacheTransport struct {
+	transport http.RoundTripper
+	log       *zerolog.Logger
+	dir       string
+	profile   string
+}
+
+// key returns the cache file name for the request. Different auth profiles
+// may see different data, so the profile is part of the key.
+func (t *cacheTransport) key(req *http.Request) string {
+	sum := sha256.Sum256([]byte(t.profile + "\n" + req.Header.Get("Accept") + "\n" + req.URL.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// load returns the cached entry for a key, if any.
+func (t *cacheTransport) load(key string) *cacheEntry {
+	data, err := ioutil.ReadFile(path.Join(t.dir, key+".json"))
+	if err != nil {
+		return nil
+	}
+
+	entry := &cacheEntry{}
+	if err := json.Unmarshal(data, entry); err != nil {
+		t.log.Debug().Err(err).Msg("Ignoring invalid cache entry")
+		return nil
+	}
+
+	return entry
+}
+
+// save writes an entry to the cache.
+func (t *cacheTransport) save(key string, entry *cacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(t.dir, 0700); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path.Join(t.dir, key+".json"), data, 0600)
+}
+
+// RoundTrip serves fresh responses from the cache, otherwise sends the request
+// with conditional headers and updates the cache from the response.
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return t.transport.RoundTrip(req)
+	}
+
+	if _, ok := cacheControl(req.Header)["no-store"]; ok {
+		return t.transport.RoundTrip(req)
+	}
+
+	key := t.key(req)
+	entry := t.load(key)
+
+	if entry != nil {
+		if time.Since(entry.Stored) < freshness(entry.Response.Headers, entry.Stored) {
+			t.log.Debug().Str("url", entry.URL).Msg("Serving fresh response from cache")
+			return entry.Response.httpResponse(req)
+		}
+
+		// Revalidate the stale entry. Don't modify the caller's request.
+		copied := *req
+		copied.Header = http.Header{}
+		for name, values := range req.Header {
+			copied.Header[name] = values
+		}
+		if etag := entry.Response.Headers.Get("ETag"); etag != "" && copied.Header.Get("If-None-Match") == "" {
+			copied.Header.Set("If-None-Match", etag)
+		}
+		if modified := entry.Response.Headers.Get("Last-Modified"); modified != "" && copied.Header.Get("If-Modified-Since") == "" {
+			copied.Header.Set("If-Modified-Since", modified)
+		}
+		req = &copied
+	}
+
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if entry != nil && resp.StatusCode == http.StatusNotModified {
+		t.log.Debug().Str("url", entry.URL).Msg("Revalidated cached response")
+		resp.Body.Close()
+
+		// Headers sent with the 304 replace the stored ones, e.g. to extend the
+		// freshness lifetime.
+		if entry.Response.Headers == nil {
+			entry.Response.Headers = http.Header{}
+		}
+		for name, values := range resp.Header {
+			entry.Response.Headers[name] = values
+		}
+		entry.Stored = time.Now().UTC()
+
+		if err := t.save(key, entry); err != nil {
+			return nil, err
+		}
+
+		return entry.Response.httpResponse(req)
+	}
+
+	if !cacheable(resp) {
+		return resp, nil
+	}
+
+	body, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	entry = &cacheEntry{
+		URL:    req.URL.String(),
+		Stored: time.Now().UTC(),
+		Response: CassetteResponse{
+			Status:  resp.StatusCode,
+			Headers: resp.Header,
+		},
+	}
+	entry.Response.setBody(body)
+
+	if err := t.save(key, entry); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// HTTPCacheMiddleware caches responses to `GET` requests on disk when
+// `http-cache` is set, honoring `Cache-Control` and `Expires` and
+// revalidating stale responses using `If-None-Match` or `If-Modified-Since`.
+// Responses are cached per auth profile. Pass `--no-cache` to bypass the
+// cache. Must be registered after `LogMiddleware`.
+func HTTPCacheMiddleware() {
+	Client.UseHandler("before dial", func(ctx *context.Context, h context.Handler) {
+		if !viper.GetBool("http-cache") || viper.GetBool("no-cache") {
+			h.Next(ctx)
+			return
+		}
+
+		transport := ctx.Client.Transport
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+
+		ctx.Client.Transport = &cacheTransport{
+			transport: transport,
+			log:       ctx.Get("log").(*zerolog.Logger),
+			dir:       path.Join(viper.GetString("config-directory"), "http-cache"),
+			profile:   viper.GetString("profile"),
+		}
+
+		h.Next(ctx)
+	})
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFreshness(t *testing.T) {
+	now := time.Now()
+
+	assert.Equal(t, 60*time.Second, freshness(http.Header{"Cache-Control": []string{"public, max-age=60"}}, now))
+	assert.Equal(t, 50*time.Second, freshness(http.Header{"Cache-Control": []string{"max-age=60"}, "Age": []string{"10"}}, now))
+	assert.Equal(t, time.Duration(0), freshness(http.Header{"Cache-Control": []string{"no-cache, max-age=60"}}, now))
+	assert.Equal(t, time.Duration(0), freshness(http.Header{"Expires": []string{"0"}}, now))
+	assert.Equal(t, time.Hour, freshness(http.Header{
+		"Date":    []string{now.UTC().Format(http.TimeFormat)},
+		"Expires": []string{now.Add(time.Hour).UTC().Format(http.TimeFormat)},
+	}, now))
+}
+
+func TestHTTPCache(t *testing.T) {
+	setupTest(t)
+
+	viper.Set("http-cache", true)
+	defer viper.Set("http-cache", false)
+
+	calls := 0
+	revalidated := 0
+	maxAge := "60"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Cache-Control", "max-age="+maxAge)
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			revalidated++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte(`{"id": 1}`))
+	}))
+	defer server.Close()
+
+	// The first request is stored and the second served from the cache.
+	for i := 0; i < 2; i++ {
+		resp, err := Client.Get().URL(server.URL + "/items/1").Do()
+		assert.NoError(t, err)
+		assert.Equal(t, `{"id": 1}`, resp.String())
+	}
+	assert.Equal(t, 1, calls)
+
+	// Bypassing the cache always sends the request.
+	viper.Set("no-cache", true)
+	_, err := Client.Get().URL(server.URL + "/items/1").Do()
+	viper.Set("no-cache", false)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	// Stale responses are revalidated using the stored ETag.
+	maxAge = "0"
+	_, err = Client.Get().URL(server.URL + "/items/2").Do()
+	assert.NoError(t, err)
+
+	resp, err := Client.Get().URL(server.URL + "/items/2").Do()
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, `{"id": 1}`, resp.String())
+	assert.Equal(t, 4, calls)
+	assert.Equal(t, 1, revalidated)
+}
@@ -0,0 +1,202 @@
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// lockStaleAfter is how long a lock file can go without being refreshed by
+// its owner before it is considered abandoned, e.g. after a crash.
+const lockStaleAfter = 10 * time.Second
+
+// lockPollInterval is how often to check whether a lock has been released.
+const lockPollInterval = 50 * time.Millisecond
+
+// Locker is an optional interface for `Storage` implementations to provide
+// locking across processes, e.g. to safely update the cache.
+type Locker interface {
+	// Lock blocks until the named lock is acquired and returns a function to
+	// release it.
+	Lock(name string) (func(), error)
+}
+
+// lockToken returns a value identifying a lock's owner.
+func lockToken() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%d-%s", os.Getpid(), hex.EncodeToString(b)), nil
+}
+
+// isLockOwner returns whether the lock file contains the given owner token.
+func isLockOwner(filename, token string) bool {
+	data, err := ioutil.ReadFile(filename)
+	return err == nil && string(data) == token
+}
+
+// breakStaleLock removes the lock file if it hasn't been refreshed by its
+// owner for a while, returning whether it did so. Several waiters may decide
+// that the lock is stale at the same time, so the file is first atomically
+// renamed to make sure that only one of them removes it.
+func breakStaleLock(filename string) bool {
+	info, err := os.Stat(filename)
+	if err != nil || time.Since(info.ModTime()) <= lockStaleAfter {
+		return false
+	}
+
+	owner, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return false
+	}
+
+	suffix, err := lockToken()
+	if err != nil {
+		return false
+	}
+
+	stale := filename + ".stale-" + suffix
+	if err := os.Rename(filename, stale); err != nil {
+		// Another waiter got here first.
+		return false
+	}
+	defer os.Remove(stale)
+
+	if moved, err := ioutil.ReadFile(stale); err != nil || string(moved) != string(owner) {
+		// Another waiter already broke the stale lock and took a new one, which
+		// was moved by mistake, so put it back.
+		os.Link(stale, filename)
+		return false
+	}
+
+	return true
+}
+
+// lockFile acquires an advisory lock by exclusively creating the given file,
+// which contains a token identifying the owner. While held, the file is
+// touched regularly so that other processes can tell a live lock from one
+// abandoned by a crashed process.
+func lockFile(filename string, timeout time.Duration) (func(), error) {
+	ctx := Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	token, err := lockToken()
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+
+	for {
+		f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err == nil {
+			_, err = f.WriteString(token)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				os.Remove(filename)
+				return nil, err
+			}
+			break
+		}
+
+		if !os.IsExist(err) {
+			return nil, err
+		}
+
+		if breakStaleLock(filename) {
+			// The owner is gone, so try again right away.
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for lock %s", filename)
+		}
+
+		select {
+		case <-time.After(lockPollInterval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(lockStaleAfter / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if isLockOwner(filename, token) {
+					now := time.Now()
+					os.Chtimes(filename, now, now)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+
+		// Only remove the lock if it is still ours, e.g. it may have been broken
+		// as stale and taken by another process if this one was suspended.
+		if isLockOwner(filename, token) {
+			os.Remove(filename)
+		}
+	}, nil
+}
+
+// writeFileAtomic writes data to a temporary file and renames it into place,
+// so readers never see a partially written file.
+func writeFileAtomic(filename string, data []byte, perm os.FileMode) error {
+	f, err := ioutil.TempFile(filepath.Dir(filename), "."+filepath.Base(filename)+".tmp")
+	if err != nil {
+		return err
+	}
+
+	// Clean up on failure. After a successful rename this is a no-op.
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(f.Name(), perm); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), filename)
+}
+
+// Lock acquires the named lock across processes if `cli.Store` supports it
+// and returns a function to release it.
+func Lock(name string) (func(), error) {
+	if locker, ok := Store.(Locker); ok {
+		return locker.Lock(name)
+	}
+
+	return func() {}, nil
+}
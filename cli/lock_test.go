@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lock")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	filename := path.Join(dir, "test.lock")
+
+	unlock, err := lockFile(filename, time.Second)
+	assert.NoError(t, err)
+
+	// A second lock times out while the first is held.
+	_, err = lockFile(filename, 100*time.Millisecond)
+	assert.Error(t, err)
+
+	// Once released, waiters acquire the lock one at a time.
+	held := 0
+	max := 0
+	mu := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, err := lockFile(filename, 5*time.Second)
+			assert.NoError(t, err)
+
+			mu.Lock()
+			held++
+			if held > max {
+				max = held
+			}
+			mu.Unlock()
+
+			time.Sleep(10 * time.Millisecond)
+
+			mu.Lock()
+			held--
+			mu.Unlock()
+			unlock()
+		}()
+	}
+
+	unlock()
+	wg.Wait()
+	assert.Equal(t, 1, max)
+
+	// Stale locks from crashed processes are broken.
+	assert.NoError(t, ioutil.WriteFile(filename, []byte("1"), 0600))
+	old := time.Now().Add(-2 * lockStaleAfter)
+	assert.NoError(t, os.Chtimes(filename, old, old))
+
+	unlock, err = lockFile(filename, 100*time.Millisecond)
+	assert.NoError(t, err)
+	unlock()
+
+	_, err = os.Stat(filename)
+	assert.True(t, os.IsNotExist(err))
+
+	// Waiters racing to break the same stale lock still hold it one at a time.
+	for round := 0; round < 10; round++ {
+		assert.NoError(t, ioutil.WriteFile(filename, []byte("1"), 0600))
+		assert.NoError(t, os.Chtimes(filename, old, old))
+
+		held, max = 0, 0
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				unlock, err := lockFile(filename, 5*time.Second)
+				assert.NoError(t, err)
+
+				mu.Lock()
+				held++
+				if held > max {
+					max = held
+				}
+				mu.Unlock()
+
+				time.Sleep(5 * time.Millisecond)
+
+				mu.Lock()
+				held--
+				mu.Unlock()
+				unlock()
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, 1, max)
+	}
+
+	// A lock taken over by another process is left alone on unlock.
+	unlock, err = lockFile(filename, time.Second)
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(filename, []byte("other"), 0600))
+	unlock()
+
+	data, err := ioutil.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, "other", string(data))
+
+	files, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestUpdateCache(t *testing.T) {
+	dir := setupTest(t)
+
+	// Another process writes to the cache after it was loaded.
+	assert.NoError(t, writeSettings("cache", map[string]interface{}{
+		"other": "value",
+	}))
+
+	assert.NoError(t, UpdateCache(func(cache *viper.Viper) {
+		cache.Set("mine", "value")
+	}))
+
+	settings, err := readSettings("cache")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"other": "value", "mine": "value"}, settings)
+
+	// Atomic writes and locks leave no temporary files behind.
+	files, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+	assert.Equal(t, "cache.json", files[0].Name())
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// processResults caches credential process output by command so it only runs
+// once per invocation of the CLI, rather than once per request.
+var processResults = struct {
+	sync.Mutex
+	byCommand map[string]map[string]string
+}{byCommand: map[string]map[string]string{}}
+
+// runCredentialProcess runs a credential process command through the shell
+// and parses its output, which must be a JSON object of profile keys to
+// values. Keys are normalized to use underscores like other profile keys.
+func runCredentialProcess(command string) (map[string]string, error) {
+	processResults.Lock()
+	defer processResults.Unlock()
+
+	if values, ok := processResults.byCommand[command]; ok {
+		return values, nil
+	}
+
+	ctx := Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("credential process failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	decoded := make(map[string]interface{})
+	if err := json.Unmarshal(out, &decoded); err != nil {
+		return nil, fmt.Errorf("credential process output must be a JSON object: %v", err)
+	}
+
+	values := make(map[string]string, len(decoded))
+	for k, v := range decoded {
+		key := strings.ToLower(strings.Replace(k, "-", "_", -1))
+		if s, ok := v.(string); ok {
+			values[key] = s
+		} else {
+			values[key] = fmt.Sprintf("%v", v)
+		}
+	}
+
+	processResults.byCommand[command] = values
+
+	return values, nil
+}
+
+// LoadProfile returns the current profile's configuration. If the profile
+// has a `credential_process` set, then the command is run and the values
+// from its JSON output override those stored in the credentials file.
+func LoadProfile() (map[string]string, error) {
+	profile := Creds.GetStringMapString("profiles." + profileName())
+
+	if command := profile["credential_process"]; command != "" {
+		values, err := runCredentialProcess(command)
+		if err != nil {
+			return profile, err
+		}
+
+		for k, v := range values {
+			profile[k] = v
+		}
+	}
+
+	return profile, nil
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCredentialProcess(t *testing.T) {
+	Creds = &CredentialsFile{viper.New(), []string{}, []string{}}
+	defer func() { Creds = nil }()
+
+	viper.Set("profile", "vault")
+	defer viper.Set("profile", "default")
+
+	Creds.Set("profiles.vault.client_id", "id1")
+	Creds.Set("profiles.vault.client_secret", "stored")
+	Creds.Set("profiles.vault.credential_process", `echo '{"Client-Secret": "from-vault", "port": 8080}'`)
+
+	profile, err := LoadProfile()
+	assert.NoError(t, err)
+	assert.Equal(t, "id1", profile["client_id"])
+	assert.Equal(t, "from-vault", profile["client_secret"])
+	assert.Equal(t, "8080", profile["port"])
+
+	Creds.Set("profiles.vault.credential_process", "echo 'denied' >&2; exit 1")
+	_, err = LoadProfile()
+	assert.EqualError(t, err, "credential process failed: exit status 1: denied")
+
+	Creds.Set("profiles.vault.credential_process", "echo not-json")
+	_, err = LoadProfile()
+	assert.Error(t, err)
+}
+
+func TestCredentialProcessProfile(t *testing.T) {
+	setupTest(t)
+	UseAuth("test", &testAuth{})
+
+	execute("auth add-profile test default --credential-process true")
+	assert.Equal(t, "true", Creds.GetString("profiles.default.credential_process"))
+	assert.False(t, Creds.IsSet("profiles.default.client_secret"))
+
+	// Profiles without stored keys can still be listed.
+	assert.NotPanics(t, func() {
+		execute("auth list-profiles")
+	})
+}
@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/h2non/gentleman.v2/context"
+)
+
+// profileSettings are settings which can be set in a profile in addition to
+// any global flag, e.g. `server` or `output_format`.
+var profileSettings = map[string]bool{
+	"server-index":     true,
+	"server-variables": true,
+	"headers":          true,
+}
+
+// envVarPrefix is the prefix for config environment variables.
+var envVarPrefix string
+
+// profileName returns the name of the current profile. Periods are replaced
+// since Viper would otherwise treat the name as a nested key.
+func profileName() string {
+	return strings.Replace(viper.GetString("profile"), ".", "-", -1)
+}
+
+// ProfileSettings returns the raw settings for the current profile, merged
+// from the `profiles` section of the config file and of the credentials file.
+// Values in the credentials file take precedence.
+func ProfileSettings() map[string]interface{} {
+	settings := make(map[string]interface{})
+
+	for k, v := range viper.GetStringMap("profiles." + profileName()) {
+		settings[k] = v
+	}
+
+	if Creds != nil {
+		for k, v := range Creds.GetStringMap("profiles." + profileName()) {
+			settings[k] = v
+		}
+	}
+
+	return settings
+}
+
+// envSet returns whether the environment variable for a config key is set.
+func envSet(name string) bool {
+	env := strings.ToUpper(strings.Replace(name, "-", "_", -1))
+	if envVarPrefix != "" {
+		env = strings.ToUpper(envVarPrefix) + "_" + env
+	}
+
+	_, ok := os.LookupEnv(env)
+	return ok
+}
+
+// applyProfile sets config values like the server and output defaults from
+// the current profile. Flags passed on the command line and environment
+// variables take precedence over the profile.
+func applyProfile() {
+	for key, value := range ProfileSettings() {
+		name := strings.Replace(key, "_", "-", -1)
+		if name == "profile" {
+			continue
+		}
+
+		flag := Root.PersistentFlags().Lookup(name)
+		if flag == nil && !profileSettings[name] {
+			// Not a setting, e.g. an auth key like `client_id`.
+			continue
+		}
+
+		if (flag != nil && flag.Changed) || envSet(name) {
+			continue
+		}
+
+		viper.Set(name, value)
+	}
+}
+
+// serverVariable matches a variable like `{region}` in a server URL.
+var serverVariable = regexp.MustCompile(`\{([^}]+)\}`)
+
+// ServerURL expands variables like `{region}` in a server URL. Values come
+// from the `server-variables` config, e.g. set in the current profile, and
+// fall back to the given defaults from the OpenAPI spec. Variable names are
+// case-insensitive.
+func ServerURL(url string, defaults map[string]string) string {
+	values := make(map[string]string)
+	for k, v := range defaults {
+		values[strings.ToLower(k)] = v
+	}
+	for k, v := range viper.GetStringMapString("server-variables") {
+		values[strings.ToLower(k)] = v
+	}
+
+	return serverVariable.ReplaceAllStringFunc(url, func(match string) string {
+		if value, ok := values[strings.ToLower(match[1:len(match)-1])]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// ProfileMiddleware adds the `headers` config, e.g. set in the current
+// profile, to every request. Headers already set on the request are kept.
+func ProfileMiddleware() {
+	Client.UseRequest(func(ctx *context.Context, h context.Handler) {
+		for name, value := range viper.GetStringMapString("headers") {
+			if ctx.Request.Header.Get(name) == "" {
+				ctx.Request.Header.Set(name, value)
+			}
+		}
+
+		h.Next(ctx)
+	})
+}
+
+// profileExists returns whether a profile is defined in either the config or
+// credentials files.
+func profileExists(name string) bool {
+	if viper.IsSet("profiles." + name) {
+		return true
+	}
+
+	return Creds != nil && Creds.IsSet("profiles."+name)
+}
+
+// configFile returns the user's config file to write to, preferring the one
+// that was loaded if it is in the config directory.
+func configFile() string {
+	dir := viper.GetString("config-directory")
+
+	if used := viper.ConfigFileUsed(); used != "" && filepath.Dir(used) == filepath.Clean(dir) {
+		return used
+	}
+
+	return path.Join(dir, "config.json")
+}
+
+// setDefaultProfile persists the default profile in the user's config file
+// and returns the normalized profile name.
+func setDefaultProfile(name string) (string, error) {
+	name = strings.Replace(name, ".", "-", -1)
+	if name != "default" && !profileExists(name) {
+		return "", fmt.Errorf("unknown profile %s", name)
+	}
+
+	// Only write what is in the file rather than all settings, which would
+	// include defaults and flags.
+	filename := configFile()
+	config := viper.New()
+	config.SetConfigFile(filename)
+	if _, err := os.Stat(filename); err == nil {
+		if err := config.ReadInConfig(); err != nil {
+			return "", err
+		}
+	}
+
+	config.Set("profile", name)
+	if err := config.WriteConfigAs(filename); err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
+
+// initProfileCommand sets up the `profile` command to manage the default
+// profile.
+func initProfileCommand() {
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Profile settings",
+	}
+	Root.AddCommand(cmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "use <name>",
+		Short: "Set the default profile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, err := setDefaultProfile(args[0])
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(Stdout, "Default profile set to %s\n", name)
+			return nil
+		},
+	})
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyProfile(t *testing.T) {
+	Init(&Config{
+		AppName: "test",
+	})
+
+	viper.Set("profiles", map[string]interface{}{
+		"staging": map[string]interface{}{
+			"server":           "https://{region}.staging.example.com",
+			"output_format":    "yaml",
+			"query":            "items",
+			"client_id":        "abc",
+			"server_variables": map[string]interface{}{"region": "eu"},
+			"headers":          map[string]interface{}{"X-Env": "staging"},
+		},
+	})
+	viper.Set("profile", "staging")
+	defer viper.Set("profiles", map[string]interface{}{})
+	defer viper.Set("profile", "default")
+
+	// Flags passed on the command line win over the profile.
+	assert.NoError(t, Root.PersistentFlags().Set("query", "id"))
+
+	applyProfile()
+	defer viper.Set("server", "")
+	defer viper.Set("output-format", "json")
+	defer viper.Set("query", "")
+	defer viper.Set("server-variables", map[string]string{})
+	defer viper.Set("headers", map[string]string{})
+
+	assert.Equal(t, "yaml", viper.GetString("output-format"))
+	assert.Equal(t, "id", viper.GetString("query"))
+	assert.False(t, viper.IsSet("client-id"))
+	assert.Equal(t, "https://eu.staging.example.com/v1", ServerURL(viper.GetString("server")+"/v1", nil))
+	assert.Equal(t, "https://eu.example.com/v2/{other}", ServerURL("https://{Region}.example.com/{version}/{other}", map[string]string{"region": "us", "version": "v2"}))
+
+	var header string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("X-Env")
+	}))
+	defer server.Close()
+
+	_, err := Client.Get().URL(server.URL).Do()
+	assert.NoError(t, err)
+	assert.Equal(t, "staging", header)
+}
+
+func TestProfileUse(t *testing.T) {
+	dir := setupTest(t)
+
+	viper.Set("profiles", map[string]interface{}{
+		"prod": map[string]interface{}{"server": "https://example.com"},
+	})
+	defer viper.Set("profiles", map[string]interface{}{})
+
+	out := &bytes.Buffer{}
+	Stdout = out
+
+	Root.SetArgs([]string{"profile", "use", "missing"})
+	assert.Error(t, Root.Execute())
+
+	Root.SetArgs([]string{"profile", "use", "prod"})
+	assert.NoError(t, Root.Execute())
+	assert.Equal(t, "Default profile set to prod\n", out.String())
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "config.json"))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"profile": "prod"}`, string(data))
+}
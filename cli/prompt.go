@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	isatty "github.com/mattn/go-isatty"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// Stdin is used to read values which are not passed as arguments or flags,
+// e.g. when adding a profile. It defaults to `os.Stdin`.
+var Stdin io.Reader = os.Stdin
+
+// isTerminal returns whether the reader is an interactive terminal.
+func isTerminal(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	if !ok {
+		return false
+	}
+
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// lineResult is a line read in the background.
+type lineResult struct {
+	line string
+	err  error
+}
+
+// readInterruptible runs the read function in the background so that the
+// prompt returns as soon as the CLI is interrupted. The terminal state is
+// restored on interrupt since e.g. echo may have been turned off.
+func readInterruptible(f *os.File, read func() (string, error)) (string, error) {
+	fd := int(f.Fd())
+	state, err := terminal.GetState(fd)
+	if err != nil {
+		// Not a terminal which can be controlled, e.g. a Cygwin terminal.
+		return read()
+	}
+
+	done := make(chan lineResult, 1)
+	go func() {
+		line, err := read()
+		done <- lineResult{line, err}
+	}()
+
+	select {
+	case result := <-done:
+		return result.line, result.err
+	case <-Context.Done():
+		terminal.Restore(fd, state)
+		fmt.Fprintln(Stderr)
+		return "", Context.Err()
+	}
+}
+
+// prompter reads values either interactively from a terminal or, when input
+// is piped, one value per line without showing any prompts.
+type prompter struct {
+	in          io.Reader
+	reader      *bufio.Reader
+	interactive bool
+}
+
+// newPrompter creates a prompter reading from `Stdin`.
+func newPrompter() *prompter {
+	return &prompter{
+		in:          Stdin,
+		reader:      bufio.NewReader(Stdin),
+		interactive: isTerminal(Stdin),
+	}
+}
+
+// Prompt reads a single value. Secret values are not echoed to the terminal.
+func (p *prompter) Prompt(label string, secret bool) (string, error) {
+	if p.interactive {
+		fmt.Fprintf(Stderr, "%s: ", label)
+
+		return readInterruptible(p.in.(*os.File), func() (string, error) {
+			if secret {
+				value, err := terminal.ReadPassword(int(p.in.(*os.File).Fd()))
+				// The newline typed by the user was not echoed.
+				fmt.Fprintln(Stderr)
+				return string(value), err
+			}
+
+			return p.readLine(label)
+		})
+	}
+
+	return p.readLine(label)
+}
+
+// readLine reads the next line of input, without the line ending.
+func (p *prompter) readLine(label string) (string, error) {
+	line, err := p.reader.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		if err == io.EOF {
+			return "", fmt.Errorf("missing value for %s", label)
+		}
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// promptLabel converts a profile key like `client_secret` into a label like
+// `Client secret`.
+func promptLabel(key string) string {
+	label := strings.Replace(strings.Replace(key, "_", " ", -1), "-", " ", -1)
+	if label == "" {
+		return label
+	}
+
+	return strings.ToUpper(label[:1]) + label[1:]
+}
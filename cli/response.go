@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/chroma/quick"
+	"github.com/spf13/viper"
+	gentleman "gopkg.in/h2non/gentleman.v2"
+)
+
+// FormatResponse outputs the response using the configured `Formatter`. If
+// `include` is set, then the status line and headers are printed before the
+// body. If `full-response` is set, then the body is wrapped together with the
+// response status and headers, so they can be selected via `--query`.
+// Streaming responses are formatted item by item as they arrive. Nothing is
+// output during a dry run.
+func FormatResponse(resp *gentleman.Response, data interface{}) error {
+	if viper.GetBool("dry-run") {
+		// The request was printed instead of being sent, so there is no response.
+		return nil
+	}
+
+	if err := IncludeResponseHead(resp); err != nil {
+		return err
+	}
+
+	format := Formatter.Format
+
+	if viper.GetBool("full-response") {
+		headers := responseHeaders(resp.Header)
+		format = func(body interface{}) error {
+			return Formatter.Format(map[string]interface{}{
+				"status":  float64(resp.StatusCode),
+				"headers": headers,
+				"body":    body,
+			})
+		}
+	}
+
+	if IsStreaming(resp) {
+		return StreamResponse(resp, format)
+	}
+
+	return format(data)
+}
+
+// IncludeResponseHead prints the response status line and headers if
+// `include` is set. Generated commands call this for error responses, which
+// are returned as errors rather than being formatted.
+func IncludeResponseHead(resp *gentleman.Response) error {
+	if !viper.GetBool("include") || viper.GetBool("dry-run") {
+		return nil
+	}
+
+	return printResponseHead(resp)
+}
+
+// responseHeaders converts headers into a structure suitable for JMESPath
+// queries. Header names are lowercased so they are predictable, e.g.
+// `headers.etag`. Headers with a single value map to a string, while
+// repeated headers map to a list of strings.
+func responseHeaders(header http.Header) map[string]interface{} {
+	headers := make(map[string]interface{}, len(header))
+
+	for name, values := range header {
+		name = strings.ToLower(name)
+
+		if len(values) == 1 {
+			headers[name] = values[0]
+			continue
+		}
+
+		list := make([]interface{}, 0, len(values))
+		for _, v := range values {
+			list = append(list, v)
+		}
+		headers[name] = list
+	}
+
+	return headers
+}
+
+// printResponseHead prints the response status line and sorted headers,
+// followed by a blank line like a raw HTTP response.
+func printResponseHead(resp *gentleman.Response) error {
+	proto := "HTTP/1.1"
+	status := fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	if resp.RawResponse != nil && resp.RawResponse.Status != "" {
+		proto = resp.RawResponse.Proto
+		status = resp.RawResponse.Status
+	}
+
+	names := make([]string, 0, len(resp.Header))
+	for name := range resp.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sb := strings.Builder{}
+	sb.WriteString(proto + " " + status + "\n")
+	for _, name := range names {
+		for _, value := range resp.Header[name] {
+			sb.WriteString(name + ": " + value + "\n")
+		}
+	}
+	sb.WriteString("\n")
+
+	if tty {
+		return quick.Highlight(Stdout, sb.String(), "http", "terminal256", "cli-dark")
+	}
+
+	_, err := fmt.Fprint(Stdout, sb.String())
+	return err
+}
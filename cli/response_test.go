@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatResponse(t *testing.T) {
+	setupTest(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", "/items/1")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 1}`))
+	}))
+	defer server.Close()
+
+	resp, err := Client.Get().URL(server.URL).Do()
+	assert.NoError(t, err)
+
+	var decoded interface{}
+	assert.NoError(t, UnmarshalResponse(resp, &decoded))
+
+	out := &bytes.Buffer{}
+	Stdout = out
+
+	viper.Set("include", true)
+	defer viper.Set("include", false)
+
+	assert.NoError(t, FormatResponse(resp, decoded))
+	assert.Contains(t, out.String(), "HTTP/1.1 201 Created\n")
+	assert.Contains(t, out.String(), "Location: /items/1\n")
+	assert.Contains(t, out.String(), "\n\n{\n  \"id\": 1\n}\n")
+
+	out.Reset()
+	viper.Set("include", false)
+	viper.Set("full-response", true)
+	viper.Set("query", "[status, headers.location, body.id]")
+	defer viper.Set("full-response", false)
+	defer viper.Set("query", "")
+
+	assert.NoError(t, FormatResponse(resp, decoded))
+	assert.JSONEq(t, `[201, "/items/1", 1]`, out.String())
+}
+
+func TestIncludeResponseHead(t *testing.T) {
+	setupTest(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "abc")
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	resp, err := Client.Get().URL(server.URL).Do()
+	assert.NoError(t, err)
+
+	out := &bytes.Buffer{}
+	Stdout = out
+
+	assert.NoError(t, IncludeResponseHead(resp))
+	assert.Empty(t, out.String())
+
+	viper.Set("include", true)
+	defer viper.Set("include", false)
+
+	assert.NoError(t, IncludeResponseHead(resp))
+	assert.Contains(t, out.String(), "HTTP/1.1 404 Not Found\n")
+	assert.Contains(t, out.String(), "X-Request-Id: abc\n")
+}
@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"gopkg.in/h2non/gentleman.v2/context"
+)
+
+// idempotentMethods are safe to retry without the risk of duplicating side
+// effects on the server, as defined in RFC 7231 section 4.2.2.
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+}
+
+// retryTransport wraps an HTTP round tripper and retries failed requests
+// using exponential backoff with jitter.
+type retryTransport struct {
+	transport  http.RoundTripper
+	log        *zerolog.Logger
+	rnd        *rand.Rand
+	maxRetries int
+	backoff    time.Duration
+	maxDelay   time.Duration
+	jitter     float64
+	statuses   map[int]bool
+	allMethods bool
+}
+
+// RoundTrip sends the request, retrying on network errors and retryable
+// response status codes until the maximum number of retries is reached.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.allMethods && !idempotentMethods[req.Method] {
+		return t.transport.RoundTrip(req)
+	}
+
+	// The body can only be read once, so keep a copy around to send again on
+	// each subsequent attempt.
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	attempt := 0
+	for {
+		if body != nil {
+			req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+
+		resp, err := t.transport.RoundTrip(req)
+
+		if attempt >= t.maxRetries || (err == nil && !t.statuses[resp.StatusCode]) {
+			return resp, err
+		}
+
+		attempt++
+		delay, ok := t.delay(attempt, resp)
+		if !ok {
+			t.log.Warn().Int("status", resp.StatusCode).Str("retry-after", resp.Header.Get("Retry-After")).Msg("Not retrying since the server asked to wait longer than the maximum delay")
+			return resp, nil
+		}
+
+		l := t.log.Warn().Int("attempt", attempt).Int("max", t.maxRetries).Str("delay", delay.String())
+		if err != nil {
+			l = l.Err(err)
+		} else {
+			l = l.Int("status", resp.StatusCode)
+
+			drainBody(resp.Body)
+		}
+		l.Msg("Retrying request")
+
+		select {
+		case <-time.After(delay):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+// delay returns how long to wait before the given attempt, preferring the
+// server's `Retry-After` header when present. Returns false if the server
+// asked to wait longer than the maximum delay, in which case the request
+// should not be retried.
+func (t *retryTransport) delay(attempt int, resp *http.Response) (time.Duration, bool) {
+	if resp != nil {
+		if after, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			if t.maxDelay > 0 && after > t.maxDelay {
+				return 0, false
+			}
+			return after, true
+		}
+	}
+
+	delay := float64(t.backoff) * math.Pow(2, float64(attempt-1))
+
+	if t.jitter > 0 {
+		// Spread out retries from many clients by randomly adjusting the delay
+		// up or down by the jitter factor.
+		delay += delay * t.jitter * (t.rnd.Float64()*2 - 1)
+	}
+
+	if t.maxDelay > 0 && delay > float64(t.maxDelay) {
+		delay = float64(t.maxDelay)
+	}
+
+	return time.Duration(delay), true
+}
+
+// parseRetryAfter parses a `Retry-After` header value, which can be either a
+// number of seconds or an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return 0, false
+}
+
+// parseStatuses parses a comma-separated list of HTTP status codes.
+func parseStatuses(value string) map[int]bool {
+	statuses := make(map[int]bool)
+
+	for _, part := range strings.Split(value, ",") {
+		if code, err := strconv.Atoi(strings.TrimSpace(part)); err == nil {
+			statuses[code] = true
+		}
+	}
+
+	return statuses
+}
+
+// RetryMiddleware retries failed requests using exponential backoff with
+// jitter, honoring any `Retry-After` response header. Only idempotent methods
+// are retried unless `retry-all-methods` is set. Must be registered after
+// `LogMiddleware` so that attempts are logged with the request's logger.
+func RetryMiddleware() {
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	Client.UseHandler("before dial", func(ctx *context.Context, h context.Handler) {
+		maxRetries := viper.GetInt("retry")
+		if maxRetries <= 0 {
+			h.Next(ctx)
+			return
+		}
+
+		transport := ctx.Client.Transport
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+
+		ctx.Client.Transport = &retryTransport{
+			transport:  transport,
+			log:        ctx.Get("log").(*zerolog.Logger),
+			rnd:        rnd,
+			maxRetries: maxRetries,
+			backoff:    viper.GetDuration("retry-backoff"),
+			maxDelay:   viper.GetDuration("retry-max-delay"),
+			jitter:     viper.GetFloat64("retry-jitter"),
+			statuses:   parseStatuses(viper.GetString("retry-status")),
+			allMethods: viper.GetBool("retry-all-methods"),
+		}
+
+		h.Next(ctx)
+	})
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetry(t *testing.T) {
+	Init(&Config{
+		AppName: "test",
+	})
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	viper.Set("retry", 2)
+	defer viper.Set("retry", 0)
+
+	resp, err := Client.Get().URL(server.URL).Do()
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 3, calls)
+}
+
+func TestRetryNonIdempotent(t *testing.T) {
+	Init(&Config{
+		AppName: "test",
+	})
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	viper.Set("retry", 2)
+	defer viper.Set("retry", 0)
+
+	resp, err := Client.Post().URL(server.URL).BodyString("{}").Do()
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryAfterTooLong(t *testing.T) {
+	Init(&Config{
+		AppName: "test",
+	})
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	viper.Set("retry", 2)
+	defer viper.Set("retry", 0)
+
+	// The server asks to wait longer than the maximum delay, so the response
+	// is returned immediately instead of blocking for an hour.
+	resp, err := Client.Get().URL(server.URL).Do()
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, 1, calls)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	delay, ok := parseRetryAfter("5")
+	assert.True(t, ok)
+	assert.Equal(t, 5*time.Second, delay)
+
+	_, ok = parseRetryAfter("invalid")
+	assert.False(t, ok)
+
+	delay, ok = parseRetryAfter(time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), delay)
+}
@@ -0,0 +1,549 @@
+package cli
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Storage loads and saves the raw contents of named settings files, like the
+// `credentials` and `cache` files. Implement this to store them elsewhere,
+// e.g. in an OS keychain, and pass it to `cli.SetStore()` after calling
+// `cli.Init()`.
+type Storage interface {
+	// Load returns the stored data, or nil if nothing has been stored yet.
+	Load(name string) ([]byte, error)
+
+	// Save replaces the stored data.
+	Save(name string, data []byte) error
+}
+
+// FileStorage stores settings as JSON files in a directory. Files are
+// written atomically and can be locked across processes.
+type FileStorage struct {
+	// Dir is the directory to store files in. Defaults to the CLI's config
+	// directory.
+	Dir string
+
+	// LockTimeout is how long to wait for a lock. Defaults to two minutes.
+	LockTimeout time.Duration
+}
+
+// Filename returns the path to the file used to store the named settings.
+func (s *FileStorage) Filename(name string) string {
+	dir := s.Dir
+	if dir == "" {
+		dir = viper.GetString("config-directory")
+	}
+
+	return path.Join(dir, name+".json")
+}
+
+// Load reads the named settings file. If there is no JSON file, then settings
+// in any other format supported by Viper, e.g. `credentials.yaml`, are read
+// and converted. They are saved as JSON from then on.
+func (s *FileStorage) Load(name string) ([]byte, error) {
+	filename := s.Filename(name)
+
+	data, err := ioutil.ReadFile(filename)
+	if !os.IsNotExist(err) {
+		return data, err
+	}
+
+	base := strings.TrimSuffix(filename, ".json")
+	for _, ext := range viper.SupportedExts {
+		if _, err := os.Stat(base + "." + ext); err != nil {
+			continue
+		}
+
+		v := viper.New()
+		v.SetConfigFile(base + "." + ext)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, err
+		}
+
+		return json.Marshal(v.AllSettings())
+	}
+
+	return nil, nil
+}
+
+// Save atomically replaces the named settings file.
+func (s *FileStorage) Save(name string, data []byte) error {
+	return writeFileAtomic(s.Filename(name), data, 0600)
+}
+
+// Lock acquires the named lock using a lock file next to the settings files.
+func (s *FileStorage) Lock(name string) (func(), error) {
+	timeout := s.LockTimeout
+	if timeout == 0 {
+		timeout = 2 * time.Minute
+	}
+
+	return lockFile(strings.TrimSuffix(s.Filename(name), ".json")+".lock", timeout)
+}
+
+// Store is the storage used for the credentials and cache files. Use
+// `cli.SetStore()` to change it.
+var Store Storage = &FileStorage{}
+
+// SetStore changes the storage used for the credentials and cache files and
+// reloads them from it.
+func SetStore(store Storage) {
+	Store = store
+
+	ReloadCache()
+	if Creds != nil {
+		reloadCreds()
+	}
+}
+
+// Parameters for deriving an encryption key from a passphrase with scrypt.
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// envelope is the stored format of encrypted settings.
+type envelope struct {
+	Cipher     string `json:"cipher"`
+	KDF        string `json:"kdf"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// ErrNoPassphrase is returned when settings are encrypted but no passphrase
+// or key file has been configured.
+var ErrNoPassphrase = errors.New("no encryption passphrase, set encryption-passphrase or encryption-key-file")
+
+// encryption tracks which settings are stored encrypted, so that they are
+// saved the same way, and the passphrase used for them.
+var encryption = struct {
+	sync.Mutex
+	encrypted  map[string]bool
+	passphrase []byte
+}{encrypted: map[string]bool{}}
+
+// unreadable tracks settings which could not be loaded, e.g. because of a
+// wrong passphrase. These are never saved, since that would replace the
+// stored values with empty settings.
+var unreadable = struct {
+	sync.Mutex
+	errors map[string]error
+}{errors: map[string]error{}}
+
+// deriveKey derives a secretbox key from the passphrase using scrypt.
+func deriveKey(passphrase []byte, e *envelope) (*[32]byte, error) {
+	derived, err := scrypt.Key(passphrase, e.Salt, e.N, e.R, e.P, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	key := new([32]byte)
+	copy(key[:], derived)
+
+	return key, nil
+}
+
+// encryptionPassphrase returns the configured passphrase, either directly
+// from the `encryption-passphrase` config or read from the file given by
+// `encryption-key-file`.
+func encryptionPassphrase() ([]byte, error) {
+	encryption.Lock()
+	defer encryption.Unlock()
+
+	if encryption.passphrase != nil {
+		return encryption.passphrase, nil
+	}
+
+	if passphrase := viper.GetString("encryption-passphrase"); passphrase != "" {
+		return []byte(passphrase), nil
+	}
+
+	if filename := viper.GetString("encryption-key-file"); filename != "" {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		return bytes.TrimSpace(data), nil
+	}
+
+	return nil, ErrNoPassphrase
+}
+
+// setPassphrase sets the passphrase to use, e.g. after prompting for it.
+func setPassphrase(passphrase []byte) {
+	encryption.Lock()
+	defer encryption.Unlock()
+
+	encryption.passphrase = passphrase
+}
+
+// isEncrypted returns whether stored data is encrypted.
+func isEncrypted(data []byte) bool {
+	e := envelope{}
+	return json.Unmarshal(data, &e) == nil && e.Cipher != "" && e.Ciphertext != nil
+}
+
+// encrypt seals the data using NaCl secretbox with a key derived from the
+// passphrase.
+func encrypt(data, passphrase []byte) ([]byte, error) {
+	e := envelope{
+		Cipher: "nacl-secretbox",
+		KDF:    "scrypt",
+		N:      scryptN,
+		R:      scryptR,
+		P:      scryptP,
+		Salt:   make([]byte, 16),
+		Nonce:  make([]byte, 24),
+	}
+
+	if _, err := rand.Read(e.Salt); err != nil {
+		return nil, err
+	}
+
+	if _, err := rand.Read(e.Nonce); err != nil {
+		return nil, err
+	}
+
+	key, err := deriveKey(passphrase, &e)
+	if err != nil {
+		return nil, err
+	}
+
+	var nonce [24]byte
+	copy(nonce[:], e.Nonce)
+	e.Ciphertext = secretbox.Seal(nil, data, &nonce, key)
+
+	return json.MarshalIndent(e, "", "  ")
+}
+
+// decrypt opens data sealed by `encrypt`.
+func decrypt(data, passphrase []byte) ([]byte, error) {
+	e := envelope{}
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+
+	if e.Cipher != "nacl-secretbox" || e.KDF != "scrypt" {
+		return nil, fmt.Errorf("unsupported encryption %s with %s", e.Cipher, e.KDF)
+	}
+
+	if len(e.Nonce) != 24 {
+		return nil, errors.New("invalid nonce")
+	}
+
+	key, err := deriveKey(passphrase, &e)
+	if err != nil {
+		return nil, err
+	}
+
+	var nonce [24]byte
+	copy(nonce[:], e.Nonce)
+	plain, ok := secretbox.Open(nil, e.Ciphertext, &nonce, key)
+	if !ok {
+		return nil, errors.New("could not decrypt, wrong passphrase?")
+	}
+
+	return plain, nil
+}
+
+// readSettings loads the named settings, remembering whether they could be
+// read so that unreadable settings are never overwritten.
+func readSettings(name string) (map[string]interface{}, error) {
+	settings, err := decodeSettings(name)
+
+	unreadable.Lock()
+	if err != nil {
+		unreadable.errors[name] = err
+	} else {
+		delete(unreadable.errors, name)
+	}
+	unreadable.Unlock()
+
+	return settings, err
+}
+
+// decodeSettings loads and if needed decrypts the named settings.
+func decodeSettings(name string) (map[string]interface{}, error) {
+	data, err := Store.Load(name)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := make(map[string]interface{})
+	if len(bytes.TrimSpace(data)) == 0 {
+		return settings, nil
+	}
+
+	encrypted := isEncrypted(data)
+
+	encryption.Lock()
+	encryption.encrypted[name] = encrypted
+	encryption.Unlock()
+
+	if encrypted {
+		passphrase, err := encryptionPassphrase()
+		if err != nil {
+			return nil, err
+		}
+
+		if data, err = decrypt(data, passphrase); err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+	}
+
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+
+	return settings, nil
+}
+
+// writeSettings saves the named settings, encrypting them if they were
+// loaded encrypted or have been marked for encryption.
+func writeSettings(name string, settings map[string]interface{}) error {
+	unreadable.Lock()
+	loadErr := unreadable.errors[name]
+	unreadable.Unlock()
+
+	if loadErr != nil {
+		return fmt.Errorf("not saving %s since it could not be loaded: %v", name, loadErr)
+	}
+
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	encryption.Lock()
+	encrypted := encryption.encrypted[name]
+	encryption.Unlock()
+
+	if encrypted {
+		passphrase, err := encryptionPassphrase()
+		if err != nil {
+			return err
+		}
+
+		if data, err = encrypt(data, passphrase); err != nil {
+			return err
+		}
+	}
+
+	return Store.Save(name, data)
+}
+
+// settingsFs lets `WriteConfig()` keep working for settings loaded with
+// `loadSettings` by saving them through `writeSettings` instead of writing
+// the file directly, so that encryption and custom storage are used.
+type settingsFs struct {
+	afero.Fs
+	name string
+}
+
+// OpenFile returns a file which saves the named settings when written to.
+func (fs settingsFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	return &settingsFile{name: fs.name}, nil
+}
+
+// settingsFile only supports writing a complete JSON document, which is how
+// Viper writes its config.
+type settingsFile struct {
+	afero.File
+	name string
+}
+
+func (f *settingsFile) Write(data []byte) (int, error) {
+	settings := make(map[string]interface{})
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return 0, err
+	}
+
+	unlock, err := Lock(f.name)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	if err := writeSettings(f.name, settings); err != nil {
+		return 0, err
+	}
+
+	return len(data), nil
+}
+
+func (f *settingsFile) WriteString(s string) (int, error) {
+	return f.Write([]byte(s))
+}
+
+func (f *settingsFile) Close() error {
+	return nil
+}
+
+// loadSettings creates a new Viper instance with the named settings. Errors
+// are logged, resulting in empty settings which can't be saved.
+func loadSettings(name string) *viper.Viper {
+	v := viper.New()
+	v.SetFs(settingsFs{Fs: afero.NewOsFs(), name: name})
+	v.SetConfigFile(name + ".json")
+	v.SetConfigType("json")
+
+	settings, err := readSettings(name)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Could not load %s", name)
+		return v
+	}
+
+	data, _ := json.Marshal(settings)
+	v.ReadConfig(bytes.NewReader(data))
+
+	return v
+}
+
+// saveLocked writes all settings while holding the named lock.
+func saveLocked(name string, v *viper.Viper) error {
+	unlock, err := Lock(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return writeSettings(name, v.AllSettings())
+}
+
+// SaveCache writes `cli.Cache` to storage, like `cli.Cache.WriteConfig()`. To
+// avoid overwriting values written by other processes in the meantime, use
+// `cli.UpdateCache` instead.
+func SaveCache() error {
+	return saveLocked("cache", Cache)
+}
+
+// ReloadCache discards cached values and reads them again from storage.
+func ReloadCache() {
+	Cache = loadSettings("cache")
+}
+
+// UpdateCache safely modifies the cache when multiple processes may be using
+// it at the same time. It locks the cache, reloads it from storage, calls
+// the update function to set new values, and then saves it.
+func UpdateCache(update func(cache *viper.Viper)) error {
+	unlock, err := Lock("cache")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	ReloadCache()
+	update(Cache)
+
+	return writeSettings("cache", Cache.AllSettings())
+}
+
+// Save writes the credentials to storage, like `cli.Creds.WriteConfig()`.
+func (cf *CredentialsFile) Save() error {
+	return saveLocked("credentials", cf.Viper)
+}
+
+// setEncrypted converts the named settings to be stored encrypted or as
+// plain text.
+func setEncrypted(name string, encrypted bool) error {
+	unlock, err := Lock(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	settings, err := readSettings(name)
+	if err != nil {
+		return err
+	}
+
+	encryption.Lock()
+	encryption.encrypted[name] = encrypted
+	encryption.Unlock()
+
+	return writeSettings(name, settings)
+}
+
+// initEncryptionCommands sets up the `auth encrypt` and `auth decrypt`
+// commands to migrate the stored credentials and cache.
+func initEncryptionCommands() {
+	for _, encrypted := range []bool{true, false} {
+		encrypted := encrypted
+
+		use := "decrypt"
+		short := "Store credentials and cached tokens as plain text"
+		if encrypted {
+			use = "encrypt"
+			short = "Encrypt stored credentials and cached tokens"
+		}
+
+		authCommand.AddCommand(&cobra.Command{
+			Use:   use,
+			Short: short,
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				if _, err := encryptionPassphrase(); err == ErrNoPassphrase {
+					p := newPrompter()
+					if !p.interactive {
+						return err
+					}
+
+					passphrase, err := p.Prompt("Passphrase", true)
+					if err != nil {
+						return err
+					}
+
+					if encrypted {
+						confirm, err := p.Prompt("Confirm passphrase", true)
+						if err != nil {
+							return err
+						}
+
+						if confirm != passphrase {
+							return errors.New("passphrases do not match")
+						}
+					}
+
+					if strings.TrimSpace(passphrase) == "" {
+						return ErrNoPassphrase
+					}
+
+					setPassphrase([]byte(passphrase))
+				}
+
+				for _, name := range []string{"credentials", "cache"} {
+					if err := setEncrypted(name, encrypted); err != nil {
+						return err
+					}
+				}
+
+				reloadCreds()
+				ReloadCache()
+
+				return nil
+			},
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	sealed, err := encrypt([]byte(`{"token": "abc"}`), []byte("secret"))
+	assert.NoError(t, err)
+	assert.True(t, isEncrypted(sealed))
+	assert.NotContains(t, string(sealed), "abc")
+
+	plain, err := decrypt(sealed, []byte("secret"))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"token": "abc"}`, string(plain))
+
+	_, err = decrypt(sealed, []byte("wrong"))
+	assert.Error(t, err)
+
+	assert.False(t, isEncrypted([]byte(`{"profiles": {}}`)))
+}
+
+func TestAuthEncrypt(t *testing.T) {
+	dir := setupTest(t)
+
+	viper.Set("encryption-passphrase", "secret")
+	defer viper.Set("encryption-passphrase", "")
+
+	UseAuth("test", &testAuth{})
+
+	execute("auth add-profile test default id1 secret1")
+	Cache.Set("profiles.default.token", "abc")
+	assert.NoError(t, SaveCache())
+
+	execute("auth encrypt")
+
+	for _, name := range []string{"credentials", "cache"} {
+		data, err := ioutil.ReadFile(filepath.Join(dir, name+".json"))
+		assert.NoError(t, err)
+		assert.True(t, isEncrypted(data))
+	}
+
+	// Values can still be read and are written back encrypted.
+	reloadCreds()
+	ReloadCache()
+	assert.Equal(t, "secret1", Creds.GetString("profiles.default.client_secret"))
+	assert.Equal(t, "abc", Cache.GetString("profiles.default.token"))
+
+	Cache.Set("profiles.default.token", "def")
+	assert.NoError(t, SaveCache())
+	data, _ := ioutil.ReadFile(filepath.Join(dir, "cache.json"))
+	assert.True(t, isEncrypted(data))
+
+	// Writing the config directly goes through the same storage.
+	Cache.Set("profiles.default.token", "ghi")
+	assert.NoError(t, Cache.WriteConfig())
+	data, _ = ioutil.ReadFile(filepath.Join(dir, "cache.json"))
+	assert.True(t, isEncrypted(data))
+	ReloadCache()
+	assert.Equal(t, "ghi", Cache.GetString("profiles.default.token"))
+
+	execute("auth decrypt")
+	data, _ = ioutil.ReadFile(filepath.Join(dir, "credentials.json"))
+	assert.Contains(t, string(data), "secret1")
+}
+
+func TestUnreadableNotOverwritten(t *testing.T) {
+	dir := setupTest(t)
+
+	viper.Set("encryption-passphrase", "secret")
+	defer viper.Set("encryption-passphrase", "")
+
+	UseAuth("test", &testAuth{})
+
+	execute("auth add-profile test default id1 secret1")
+	execute("auth encrypt")
+	sealed, _ := ioutil.ReadFile(filepath.Join(dir, "credentials.json"))
+
+	// With the wrong passphrase nothing can be loaded, and the empty settings
+	// must not replace the stored ones.
+	viper.Set("encryption-passphrase", "wrong")
+	reloadCreds()
+	assert.False(t, Creds.IsSet("profiles.default"))
+	assert.Error(t, Creds.Save())
+	assert.Error(t, Creds.WriteConfig())
+
+	data, _ := ioutil.ReadFile(filepath.Join(dir, "credentials.json"))
+	assert.Equal(t, string(sealed), string(data))
+
+	viper.Set("encryption-passphrase", "secret")
+	reloadCreds()
+	assert.Equal(t, "secret1", Creds.GetString("profiles.default.client_secret"))
+	assert.NoError(t, Creds.Save())
+}
+
+func TestLoadOtherFormats(t *testing.T) {
+	dir := setupTest(t)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "credentials.yaml"), []byte("profiles:\n  default:\n    client_id: id1\n"), 0600))
+
+	UseAuth("test", &testAuth{})
+	assert.Equal(t, "id1", Creds.GetString("profiles.default.client_id"))
+
+	// Settings are saved as JSON, which is then used instead.
+	Creds.Set("profiles.default.client_id", "id2")
+	assert.NoError(t, Creds.Save())
+	reloadCreds()
+	assert.Equal(t, "id2", Creds.GetString("profiles.default.client_id"))
+	assert.FileExists(t, filepath.Join(dir, "credentials.json"))
+}
+
+// memoryStorage keeps settings in memory.
+type memoryStorage map[string][]byte
+
+func (s memoryStorage) Load(name string) ([]byte, error) {
+	return s[name], nil
+}
+
+func (s memoryStorage) Save(name string, data []byte) error {
+	s[name] = data
+	return nil
+}
+
+func TestSetStore(t *testing.T) {
+	setupTest(t)
+	UseAuth("test", &testAuth{})
+	defer SetStore(Store)
+
+	store := memoryStorage{
+		"credentials": []byte(`{"profiles": {"default": {"client_id": "id1"}}}`),
+		"cache":       []byte(`{"profiles": {"default": {"token": "abc"}}}`),
+	}
+
+	SetStore(store)
+	assert.Equal(t, "id1", Creds.GetString("profiles.default.client_id"))
+	assert.Equal(t, "abc", Cache.GetString("profiles.default.token"))
+
+	Cache.Set("profiles.default.token", "def")
+	assert.NoError(t, Cache.WriteConfig())
+	assert.Contains(t, string(store["cache"]), "def")
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	gentleman "gopkg.in/h2non/gentleman.v2"
+)
+
+// isStreamingType returns whether the content type describes a stream of
+// items that should be processed as they arrive.
+func isStreamingType(headers http.Header) bool {
+	ct := headers.Get("content-type")
+	return strings.Contains(ct, "ndjson") || strings.Contains(ct, "x-json-stream") || strings.Contains(ct, "event-stream")
+}
+
+// IsStreaming returns whether the response is a stream of items, for example
+// newline-delimited JSON or server-sent events. The body of streaming
+// responses is left unread so it can be decoded using `StreamResponse`.
+func IsStreaming(resp *gentleman.Response) bool {
+	return resp != nil && resp.StatusCode < 400 && isStreamingType(resp.Header)
+}
+
+// StreamResponse decodes each item of a streaming response as it arrives and
+// calls the handler with it. Newline-delimited JSON and server-sent events
+// are supported. Event data which isn't valid JSON is passed as a string.
+func StreamResponse(resp *gentleman.Response, handler func(interface{}) error) error {
+	defer resp.Close()
+
+	if strings.Contains(resp.Header.Get("content-type"), "event-stream") {
+		return streamEvents(resp, handler)
+	}
+
+	decoder := json.NewDecoder(resp)
+	for {
+		var item interface{}
+		if err := decoder.Decode(&item); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		if err := handler(item); err != nil {
+			return err
+		}
+	}
+}
+
+// streamEvents parses server-sent events as described in
+// https://html.spec.whatwg.org/multipage/server-sent-events.html and calls
+// the handler with the data of each event.
+func streamEvents(r io.Reader, handler func(interface{}) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	data := make([]string, 0)
+
+	dispatch := func() error {
+		if len(data) == 0 {
+			return nil
+		}
+
+		raw := strings.Join(data, "\n")
+		data = data[:0]
+
+		var item interface{}
+		if err := json.Unmarshal([]byte(raw), &item); err != nil {
+			item = raw
+		}
+
+		return handler(item)
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if line == "" {
+			// A blank line ends the current event.
+			if err := dispatch(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if strings.HasPrefix(line, ":") {
+			// Comment, often used as a keep-alive.
+			continue
+		}
+
+		field := line
+		value := ""
+		if i := strings.Index(line, ":"); i != -1 {
+			field = line[:i]
+			value = strings.TrimPrefix(line[i+1:], " ")
+		}
+
+		// Other fields like `event`, `id` and `retry` are ignored.
+		if field == "data" {
+			data = append(data, value)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("Unable to read event stream: %v", err)
+	}
+
+	// Dispatch any trailing event without a final blank line.
+	return dispatch()
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func streamItems(t *testing.T, contentType, body string) []interface{} {
+	Init(&Config{
+		AppName: "test",
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		fmt.Fprint(w, body)
+	}))
+	defer server.Close()
+
+	resp, err := Client.Get().URL(server.URL).Do()
+	assert.NoError(t, err)
+	assert.True(t, IsStreaming(resp))
+
+	items := make([]interface{}, 0)
+	err = StreamResponse(resp, func(item interface{}) error {
+		items = append(items, item)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	return items
+}
+
+func TestStreamNDJSON(t *testing.T) {
+	items := streamItems(t, "application/x-ndjson", "{\"id\": 1}\n{\"id\": 2}\n")
+
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"id": 1.0},
+		map[string]interface{}{"id": 2.0},
+	}, items)
+}
+
+func TestStreamEvents(t *testing.T) {
+	items := streamItems(t, "text/event-stream", ": keep-alive\n\nevent: update\ndata: {\"id\": 1}\n\ndata: hello\ndata: world\n\n")
+
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"id": 1.0},
+		"hello\nworld",
+	}, items)
+}
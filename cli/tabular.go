@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	jmespath "github.com/danielgtaylor/go-jmespath-plus"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/viper"
+)
+
+// parseColumns splits a comma-separated list of column expressions.
+func parseColumns(value string) []string {
+	columns := make([]string, 0)
+
+	for _, column := range strings.Split(value, ",") {
+		if column = strings.TrimSpace(column); column != "" {
+			columns = append(columns, column)
+		}
+	}
+
+	return columns
+}
+
+// configuredColumns returns the `columns` setting. It is either a
+// comma-separated string, e.g. from the `--columns` flag, or a list of
+// expressions which may themselves contain commas, e.g. from the
+// `x-cli-table` extension.
+func configuredColumns() []string {
+	switch value := viper.Get("columns").(type) {
+	case []string:
+		return value
+	case []interface{}:
+		columns := make([]string, 0, len(value))
+		for _, column := range value {
+			columns = append(columns, fmt.Sprintf("%v", column))
+		}
+		return columns
+	}
+
+	return parseColumns(viper.GetString("columns"))
+}
+
+// tabulate flattens the data into a header and rows of cells. Each item in
+// a list becomes a row, while an object becomes a single row. Each column is
+// a JMESPath expression evaluated against the item, so nested values can be
+// selected using e.g. `owner.name`. If no columns are given, then the sorted
+// union of all top-level object keys is used. Lists of scalars are rendered
+// as a single `value` column.
+func tabulate(data interface{}, columns []string) ([]string, [][]string, error) {
+	var items []interface{}
+	if list, ok := data.([]interface{}); ok {
+		items = list
+	} else {
+		items = []interface{}{data}
+	}
+
+	keys := make(map[string]bool)
+	for _, item := range items {
+		if m, ok := item.(map[string]interface{}); ok {
+			for k := range m {
+				keys[k] = true
+			}
+		}
+	}
+
+	if len(keys) == 0 {
+		// No objects found, so this is a list of scalars and any columns do not
+		// apply, e.g. after a query like `[].id`.
+		rows := make([][]string, 0, len(items))
+		for _, item := range items {
+			rows = append(rows, []string{cell(item)})
+		}
+
+		return []string{"value"}, rows, nil
+	}
+
+	if len(columns) == 0 {
+		for k := range keys {
+			columns = append(columns, k)
+		}
+		sort.Strings(columns)
+	}
+
+	rows := make([][]string, 0, len(items))
+	for _, item := range items {
+		row := make([]string, 0, len(columns))
+		for _, column := range columns {
+			if m, ok := item.(map[string]interface{}); ok {
+				if v, ok := m[column]; ok {
+					// Fast path that also supports keys which aren't valid JMESPath.
+					row = append(row, cell(v))
+					continue
+				}
+			}
+
+			value, err := jmespath.Search(column, item)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid column %s: %v", column, err)
+			}
+			row = append(row, cell(value))
+		}
+		rows = append(rows, row)
+	}
+
+	return columns, rows, nil
+}
+
+// cell renders a single value for display. Scalars are printed as-is while
+// nested objects and arrays are encoded as compact JSON.
+func cell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int, int64:
+		return fmt.Sprintf("%d", v)
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(encoded)
+	}
+}
+
+// encodeTabular renders the data as a table, CSV or TSV.
+func encodeTabular(format string, data interface{}, columns []string) ([]byte, error) {
+	header, rows, err := tabulate(data, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+
+	switch format {
+	case "table":
+		table := tablewriter.NewWriter(buf)
+		table.SetAutoWrapText(false)
+		table.SetHeader(header)
+		table.AppendBulk(rows)
+		table.Render()
+	case "csv", "tsv":
+		w := csv.NewWriter(buf)
+		if format == "tsv" {
+			w.Comma = '\t'
+		}
+		w.Write(header)
+		w.WriteAll(rows)
+		if err := w.Error(); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTabulate(t *testing.T) {
+	var data interface{}
+	err := json.Unmarshal([]byte(`[
+		{"id": "a", "owner": {"name": "Ann"}, "tags": ["x"], "count": 1000000},
+		{"id": "b", "owner": {"name": "Bob"}, "count": 2.5}
+	]`), &data)
+	assert.NoError(t, err)
+
+	header, rows, err := tabulate(data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"count", "id", "owner", "tags"}, header)
+	assert.Equal(t, [][]string{
+		{"1000000", "a", `{"name":"Ann"}`, `["x"]`},
+		{"2.5", "b", `{"name":"Bob"}`, ""},
+	}, rows)
+
+	header, rows, err = tabulate(data, []string{"id", "owner.name"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id", "owner.name"}, header)
+	assert.Equal(t, [][]string{{"a", "Ann"}, {"b", "Bob"}}, rows)
+
+	_, _, err = tabulate(data, []string{"owner[?"})
+	assert.Error(t, err)
+}
+
+func TestTabulateScalars(t *testing.T) {
+	header, rows, err := tabulate([]interface{}{"a", 1.0, nil}, []string{"id"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"value"}, header)
+	assert.Equal(t, [][]string{{"a"}, {"1"}, {""}}, rows)
+}
+
+func TestEncodeCSV(t *testing.T) {
+	data := []interface{}{
+		map[string]interface{}{"id": "a", "name": "Hello, world"},
+	}
+
+	encoded, err := encodeTabular("csv", data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "id,name\na,\"Hello, world\"\n", string(encoded))
+
+	encoded, err = encodeTabular("tsv", data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "id\tname\na\tHello, world\n", string(encoded))
+}
+
+func TestConfiguredColumns(t *testing.T) {
+	viper.SetDefault("columns", []string{"id", "join(', ', tags)"})
+	defer viper.SetDefault("columns", "")
+
+	data := []interface{}{
+		map[string]interface{}{"id": "a", "tags": []interface{}{"x", "y"}},
+	}
+
+	encoded, err := encodeTabular("csv", data, configuredColumns())
+	assert.NoError(t, err)
+	assert.Equal(t, "id,\"join(', ', tags)\"\na,\"x, y\"\n", string(encoded))
+
+	viper.SetDefault("columns", "id, tags")
+	assert.Equal(t, []string{"id", "tags"}, configuredColumns())
+}
@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
+)
+
+// templateColors maps color names usable in output templates to terminal
+// colors, matching those used for log output.
+var templateColors = map[string]int{
+	"gray":   cLightGray,
+	"blue":   cBlue,
+	"orange": cOrange,
+	"red":    cRed,
+	"green":  cGreen,
+	"purple": cPurple,
+	"yellow": cYellow,
+}
+
+// templateFuncs returns the helper functions available to output templates.
+// Colors are only applied when `useColor` is true.
+func templateFuncs(useColor bool) template.FuncMap {
+	return template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			encoded, err := json.Marshal(v)
+			return string(encoded), err
+		},
+		"prettyjson": func(v interface{}) (string, error) {
+			encoded, err := json.MarshalIndent(v, "", "  ")
+			return string(encoded), err
+		},
+		"yaml": func(v interface{}) (string, error) {
+			encoded, err := yaml.Marshal(v)
+			return string(encoded), err
+		},
+		"table": func(v interface{}, columns ...string) (string, error) {
+			encoded, err := encodeTabular("table", v, columns)
+			return string(encoded), err
+		},
+		"color": func(name string, v interface{}) (string, error) {
+			c, ok := templateColors[name]
+			if !ok {
+				return "", fmt.Errorf("unknown color %s", name)
+			}
+			return colorize(cell(v), c, useColor), nil
+		},
+		"date":  formatDate,
+		"join":  join,
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+	}
+}
+
+// formatDate formats a date using a Go time layout like `2006-01-02`. The
+// value may be an RFC 3339 string or a number of seconds since the epoch.
+func formatDate(layout string, v interface{}) (string, error) {
+	var t time.Time
+
+	switch value := v.(type) {
+	case time.Time:
+		t = value
+	case float64:
+		t = time.Unix(int64(value), 0)
+	case int:
+		t = time.Unix(int64(value), 0)
+	case int64:
+		t = time.Unix(value, 0)
+	case string:
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return "", err
+		}
+		t = parsed
+	default:
+		return "", fmt.Errorf("cannot format %v as a date", v)
+	}
+
+	return t.Format(layout), nil
+}
+
+// join renders each item in a list and joins them with the separator.
+func join(v interface{}, sep string) string {
+	items, ok := v.([]interface{})
+	if !ok {
+		return cell(v)
+	}
+
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		parts = append(parts, cell(item))
+	}
+
+	return strings.Join(parts, sep)
+}
+
+// outputTemplate returns the output template text from the `template` or
+// `template-file` configuration values, if either is set.
+func outputTemplate() (string, error) {
+	if text := viper.GetString("template"); text != "" {
+		return text, nil
+	}
+
+	if filename := viper.GetString("template-file"); filename != "" {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+
+	return "", nil
+}
+
+// renderTemplate renders the data using a Go `text/template`.
+func renderTemplate(text string, data interface{}, useColor bool) ([]byte, error) {
+	tmpl, err := template.New("output").Funcs(templateFuncs(useColor)).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, data); err != nil {
+		return nil, err
+	}
+
+	// Make sure we end with a newline, otherwise things won't look right
+	// in the terminal.
+	if buf.Len() > 0 && !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes(), nil
+}
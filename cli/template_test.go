@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	data := map[string]interface{}{
+		"id":      "a",
+		"tags":    []interface{}{"x", "y"},
+		"created": "2020-01-02T03:04:05Z",
+		"owner":   map[string]interface{}{"name": "Ann"},
+	}
+
+	out, err := renderTemplate(`{{.id}} {{join .tags ","}} {{date "2006-01-02" .created}} {{json .owner}} {{color "red" .id}}`, data, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "a x,y 2020-01-02 {\"name\":\"Ann\"} a\n", string(out))
+
+	out, err = renderTemplate(`{{color "red" .id}}`, data, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "\x1b[38;5;204ma\x1b[0m\n", string(out))
+
+	_, err = renderTemplate(`{{color "invalid" .id}}`, data, false)
+	assert.Error(t, err)
+}
+
+func TestRenderTemplateTable(t *testing.T) {
+	data := []interface{}{
+		map[string]interface{}{"id": "a", "name": "Ann"},
+	}
+
+	out, err := renderTemplate(`{{table . "name"}}`, data, false)
+	assert.NoError(t, err)
+	assert.Contains(t, string(out), "| Ann  |")
+}
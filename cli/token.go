@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// decodeJWT decodes the header and claims of a JSON Web Token. The signature
+// is not verified, so this must only be used to inspect tokens.
+func decodeJWT(token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("token is not a JWT")
+	}
+
+	decoded := make(map[string]interface{})
+	for i, name := range []string{"header", "claims"} {
+		data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[i], "="))
+		if err != nil {
+			return nil, fmt.Errorf("token is not a JWT: %v", err)
+		}
+
+		value := make(map[string]interface{})
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, fmt.Errorf("token is not a JWT: %v", err)
+		}
+
+		decoded[name] = value
+	}
+
+	if exp, ok := decoded["claims"].(map[string]interface{})["exp"].(float64); ok {
+		expires := time.Unix(int64(exp), 0).UTC()
+		decoded["expires"] = expires.Format(time.RFC3339)
+		decoded["expired"] = time.Now().After(expires)
+	}
+
+	return decoded, nil
+}
+
+// profileToken returns the named profile's access token, fetching a new one
+// through the auth handler if it is missing or expired. The profile must be
+// the current one.
+func profileToken(name string) (string, error) {
+	handler := AuthHandlers[GetProfile()["type"]]
+	if handler == nil {
+		return "", fmt.Errorf("no handler for auth type %s", GetProfile()["type"])
+	}
+
+	req, err := authorize(name, handler)
+	if err != nil {
+		return "", err
+	}
+
+	auth := req.Header.Get("Authorization")
+	if auth == "" {
+		return "", fmt.Errorf("profile %s does not use an access token", name)
+	}
+
+	// Remove the scheme, e.g. `Bearer`, if present.
+	if i := strings.Index(auth, " "); i != -1 {
+		auth = auth[i+1:]
+	}
+
+	return auth, nil
+}
+
+// initTokenCommand sets up the `auth token` command to print the access token
+// of the current profile.
+func initTokenCommand() {
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: "Print the access token for the current profile",
+		Long:  fmt.Sprintf("Print the access token for the current profile, logging in if needed. This is useful for making requests with other tools, e.g. `curl -H \"Authorization: Bearer $(%s auth token)\"`. Pass `--decode` to show the header and claims of a JWT without verifying it.", Root.Name()),
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, err := credsProfileName(profileName())
+			if err != nil {
+				return err
+			}
+
+			token, err := profileToken(name)
+			if err != nil {
+				return err
+			}
+
+			if decode, _ := cmd.Flags().GetBool("decode"); decode {
+				decoded, err := decodeJWT(token)
+				if err != nil {
+					return err
+				}
+
+				return Formatter.Format(decoded)
+			}
+
+			fmt.Fprintln(Stdout, token)
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("decode", false, "Decode and show the JWT header, claims and expiration")
+	authCommand.AddCommand(cmd)
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// bearerAuth is an auth handler which sets a fixed bearer token and records
+// when it is logged out.
+type bearerAuth struct {
+	token     string
+	loggedOut bool
+}
+
+func (a *bearerAuth) ProfileKeys() []string {
+	return []string{}
+}
+
+func (a *bearerAuth) OnRequest(log *zerolog.Logger, request *http.Request) error {
+	request.Header.Set("Authorization", "Bearer "+a.token)
+	return nil
+}
+
+func (a *bearerAuth) Logout(log *zerolog.Logger) error {
+	a.loggedOut = true
+	return nil
+}
+
+func TestAuthToken(t *testing.T) {
+	setupTest(t)
+
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+	claims := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"user1","exp":1000}`))
+	handler := &bearerAuth{token: header + "." + claims + "."}
+
+	UseAuth("bearer", handler)
+
+	execute("auth add-profile bearer default")
+
+	assert.Equal(t, handler.token+"\n", execute("auth token"))
+	assert.JSONEq(t, `{
+		"header": {"alg": "none"},
+		"claims": {"sub": "user1", "exp": 1000},
+		"expires": "1970-01-01T00:16:40Z",
+		"expired": true
+	}`, execute("auth token --decode"))
+
+	handler.token = "opaque"
+	assert.Contains(t, execute("auth token --decode"), "token is not a JWT")
+
+	execute("auth logout")
+	assert.True(t, handler.loggedOut)
+}
@@ -0,0 +1,270 @@
+package cli
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+	"gopkg.in/h2non/gentleman.v2/context"
+)
+
+// transportSettings configures TLS and proxies for outgoing requests.
+type transportSettings struct {
+	CAFile     string
+	CertFile   string
+	KeyFile    string
+	Insecure   bool
+	MinVersion string
+	Proxy      string
+	NoProxy    string
+}
+
+// transports caches created transports by their settings so that connections
+// are reused between requests.
+var transports = struct {
+	sync.Mutex
+	byKey map[transportSettings]*http.Transport
+}{byKey: map[transportSettings]*http.Transport{}}
+
+// tlsVersions maps user-facing TLS version names to their constants.
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// transportSetting returns a transport config value. A flag passed on the
+// command line wins, followed by the current auth profile and then the
+// global config, e.g. from a config file or environment variable.
+func transportSetting(name string) string {
+	if Root == nil {
+		return viper.GetString(name)
+	}
+
+	if flag := Root.PersistentFlags().Lookup(name); flag != nil && flag.Changed {
+		return viper.GetString(name)
+	}
+
+	if Creds != nil {
+		if value, ok := GetProfile()[strings.Replace(name, "-", "_", -1)]; ok {
+			return value
+		}
+	}
+
+	return viper.GetString(name)
+}
+
+// currentTransportSettings loads the transport settings for this request.
+func currentTransportSettings() transportSettings {
+	insecure := transportSetting("insecure")
+
+	return transportSettings{
+		CAFile:     transportSetting("ca-file"),
+		CertFile:   transportSetting("cert-file"),
+		KeyFile:    transportSetting("key-file"),
+		Insecure:   insecure == "true" || insecure == "1",
+		MinVersion: transportSetting("tls-min-version"),
+		Proxy:      transportSetting("proxy"),
+		NoProxy:    transportSetting("no-proxy"),
+	}
+}
+
+// matchNoProxy returns whether a host should bypass the proxy. Entries can
+// be `*`, host names, domain suffixes like `.example.com`, IP addresses and
+// CIDR ranges.
+func matchNoProxy(noProxy, host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+	ip := net.ParseIP(host)
+
+	for _, entry := range strings.Split(noProxy, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+
+		if entry == "*" {
+			return true
+		}
+
+		if _, cidr, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && cidr.Contains(ip) {
+				return true
+			}
+			continue
+		}
+
+		if h, _, err := net.SplitHostPort(entry); err == nil {
+			entry = h
+		}
+
+		domain := strings.TrimPrefix(entry, ".")
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// proxyFunc returns the proxy function for the transport. Without an explicit
+// proxy URL the standard `HTTP_PROXY`, `HTTPS_PROXY` and `NO_PROXY`
+// environment variables are used.
+func proxyFunc(proxy, noProxy string) (func(*http.Request) (*url.URL, error), error) {
+	var proxyURL *url.URL
+	if proxy != "" {
+		parsed, err := url.Parse(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL %s: %v", proxy, err)
+		}
+		proxyURL = parsed
+	}
+
+	return func(req *http.Request) (*url.URL, error) {
+		if matchNoProxy(noProxy, req.URL.Host) {
+			return nil, nil
+		}
+
+		if proxyURL != nil {
+			return proxyURL, nil
+		}
+
+		return http.ProxyFromEnvironment(req)
+	}, nil
+}
+
+// newTransport creates an HTTP transport from the given settings, using the
+// same defaults as `http.DefaultTransport`.
+func newTransport(settings transportSettings) (*http.Transport, error) {
+	config := &tls.Config{
+		InsecureSkipVerify: settings.Insecure,
+	}
+
+	if settings.MinVersion != "" {
+		version, ok := tlsVersions[settings.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("unknown TLS version %s, expected one of: 1.0, 1.1, 1.2, 1.3", settings.MinVersion)
+		}
+		config.MinVersion = version
+	}
+
+	if settings.CAFile != "" {
+		pem, err := ioutil.ReadFile(settings.CAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", settings.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if settings.CertFile != "" || settings.KeyFile != "" {
+		if settings.CertFile == "" || settings.KeyFile == "" {
+			return nil, fmt.Errorf("both cert-file and key-file are required for client certificates")
+		}
+
+		cert, err := tls.LoadX509KeyPair(settings.CertFile, settings.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	proxy, err := proxyFunc(settings.Proxy, settings.NoProxy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Transport{
+		Proxy: proxy,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSClientConfig:       config,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}, nil
+}
+
+// Transport returns the HTTP round tripper configured via the `ca-file`,
+// `cert-file`, `key-file`, `insecure`, `tls-min-version`, `proxy` and
+// `no-proxy` settings, each of which can also be set in the current auth
+// profile. Returns `http.DefaultTransport` if nothing is configured.
+func Transport() (http.RoundTripper, error) {
+	settings := currentTransportSettings()
+	if settings == (transportSettings{}) {
+		return http.DefaultTransport, nil
+	}
+
+	transports.Lock()
+	defer transports.Unlock()
+
+	if t := transports.byKey[settings]; t != nil {
+		return t, nil
+	}
+
+	t, err := newTransport(settings)
+	if err != nil {
+		return nil, err
+	}
+	transports.byKey[settings] = t
+
+	return t, nil
+}
+
+// HTTPClient returns a new HTTP client using the configured `Transport`, for
+// requests made outside of `cli.Client` such as fetching auth tokens.
+func HTTPClient() (*http.Client, error) {
+	t, err := Transport()
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Client{
+		Transport: t,
+		Timeout:   viper.GetDuration("timeout"),
+	}, nil
+}
+
+// TransportMiddleware applies the configured TLS and proxy settings to each
+// request. Must be registered before other middleware that wraps the
+// client's transport, like `RetryMiddleware`.
+func TransportMiddleware() {
+	Client.UseHandler("before dial", func(ctx *context.Context, h context.Handler) {
+		if currentTransportSettings() == (transportSettings{}) {
+			// Keep Gentleman's default transport.
+			h.Next(ctx)
+			return
+		}
+
+		t, err := Transport()
+		if err != nil {
+			h.Error(ctx, err)
+			return
+		}
+
+		ctx.Client.Transport = t
+		h.Next(ctx)
+	})
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchNoProxy(t *testing.T) {
+	noProxy := "localhost, .internal.example.com, example.org, 10.0.0.0/8"
+
+	assert.True(t, matchNoProxy(noProxy, "localhost:8080"))
+	assert.True(t, matchNoProxy(noProxy, "api.internal.example.com"))
+	assert.True(t, matchNoProxy(noProxy, "example.org"))
+	assert.True(t, matchNoProxy(noProxy, "www.example.org"))
+	assert.True(t, matchNoProxy(noProxy, "10.1.2.3:443"))
+	assert.False(t, matchNoProxy(noProxy, "example.com"))
+	assert.False(t, matchNoProxy(noProxy, "notexample.org"))
+	assert.False(t, matchNoProxy(noProxy, "192.168.0.1"))
+	assert.True(t, matchNoProxy("*", "example.com"))
+}
+
+func TestTransportTLS(t *testing.T) {
+	Init(&Config{
+		AppName: "test",
+	})
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	// The test server's certificate is self-signed, so this fails by default.
+	_, err := Client.Get().URL(server.URL).Do()
+	assert.Error(t, err)
+
+	viper.Set("insecure", true)
+	resp, err := Client.Get().URL(server.URL).Do()
+	viper.Set("insecure", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", resp.String())
+
+	dir, err := ioutil.TempDir("", "transport")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	caFile := filepath.Join(dir, "ca.pem")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	assert.NoError(t, ioutil.WriteFile(caFile, ca, 0600))
+
+	viper.Set("ca-file", caFile)
+	defer viper.Set("ca-file", "")
+
+	client, err := HTTPClient()
+	assert.NoError(t, err)
+	res, err := client.Get(server.URL)
+	assert.NoError(t, err)
+	res.Body.Close()
+
+	viper.Set("tls-min-version", "2.0")
+	defer viper.Set("tls-min-version", "")
+	_, err = HTTPClient()
+	assert.Error(t, err)
+}
@@ -13,6 +13,7 @@ func main() {
 		Version:   "1.0.0",
 	})
 
+	openapiRegisterAuth()
 	openapiRegister(false)
 
 	cli.Root.Execute()
@@ -4,9 +4,11 @@
 package main
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/danielgtaylor/openapi-cli-generator/cli"
+	"github.com/danielgtaylor/openapi-cli-generator/httpauth"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
@@ -26,8 +28,15 @@ func openapiServers() []map[string]string {
 	}
 }
 
+func openapiServerVariables() []map[string]string {
+	return []map[string]string{
+
+		map[string]string{},
+	}
+}
+
 // OpenapiEcho echo
-func OpenapiEcho(params *viper.Viper, body string) (*gentleman.Response, map[string]interface{}, error) {
+func OpenapiEcho(ctx context.Context, params *viper.Viper, body string) (*gentleman.Response, map[string]interface{}, error) {
 	handlerPath := "echo"
 	if openapiSubcommand {
 		handlerPath = "openapi " + handlerPath
@@ -35,12 +44,13 @@ func OpenapiEcho(params *viper.Viper, body string) (*gentleman.Response, map[str
 
 	server := viper.GetString("server")
 	if server == "" {
-		server = openapiServers()[viper.GetInt("server-index")]["url"]
+		index := viper.GetInt("server-index")
+		server = cli.ServerURL(openapiServers()[index]["url"], openapiServerVariables()[index])
 	}
 
 	url := server + "/echo"
 
-	req := cli.Client.Post().URL(url)
+	req := cli.WithContext(ctx, cli.Client.Post().URL(url))
 
 	paramEchoQuery := params.GetString("echo-query")
 	if paramEchoQuery != "" {
@@ -64,12 +74,22 @@ func OpenapiEcho(params *viper.Viper, body string) (*gentleman.Response, map[str
 
 	var decoded map[string]interface{}
 
+	if viper.GetBool("dry-run") {
+		// The request was printed instead of being sent.
+		return resp, decoded, nil
+	}
+
 	if resp.StatusCode < 400 {
+		if cli.IsStreaming(resp) {
+			// Streaming bodies are decoded as items arrive using `cli.StreamResponse`.
+			return resp, decoded, nil
+		}
+
 		if err := cli.UnmarshalResponse(resp, &decoded); err != nil {
 			return nil, nil, errors.Wrap(err, "Unmarshalling response failed")
 		}
 	} else {
-		return nil, nil, errors.Errorf("HTTP %d: %s", resp.StatusCode, resp.String())
+		return resp, nil, errors.Errorf("HTTP %d: %s", resp.StatusCode, resp.String())
 	}
 
 	after := cli.HandleAfter(handlerPath, params, resp, decoded)
@@ -80,6 +100,14 @@ func OpenapiEcho(params *viper.Viper, body string) (*gentleman.Response, map[str
 	return resp, decoded, nil
 }
 
+// openapiRegisterAuth registers auth handlers for the API's HTTP security
+// schemes. Must be called *after* `cli.Init()`.
+func openapiRegisterAuth() {
+	httpauth.UseSchemes(map[string]string{
+		"bearerAuth": "bearer",
+	})
+}
+
 func openapiRegister(subcommand bool) {
 	root := cli.Root
 
@@ -114,12 +142,15 @@ func openapiRegister(subcommand bool) {
 					log.Fatal().Err(err).Msg("Unable to get body")
 				}
 
-				_, decoded, err := OpenapiEcho(params, body)
+				resp, decoded, err := OpenapiEcho(cli.Context, params, body)
 				if err != nil {
+					if resp != nil {
+						cli.IncludeResponseHead(resp)
+					}
 					log.Fatal().Err(err).Msg("Error calling operation")
 				}
 
-				if err := cli.Formatter.Format(decoded); err != nil {
+				if err := cli.FormatResponse(resp, decoded); err != nil {
 					log.Fatal().Err(err).Msg("Formatting failed")
 				}
 
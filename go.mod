@@ -23,10 +23,12 @@ require (
 	github.com/pkg/errors v0.8.1
 	github.com/rs/zerolog v1.11.0
 	github.com/sergi/go-diff v1.1.0 // indirect
+	github.com/spf13/afero v1.1.2
 	github.com/spf13/cobra v0.0.3
 	github.com/spf13/pflag v1.0.3
 	github.com/spf13/viper v1.2.1
 	github.com/stretchr/testify v1.4.0
+	golang.org/x/crypto v0.0.0-20191011191535-87dc89f01550
 	golang.org/x/oauth2 v0.0.0-20181003184128-c57b0facaced
 	google.golang.org/appengine v1.2.0 // indirect
 	gopkg.in/h2non/gentleman.v2 v2.0.3
@@ -0,0 +1,166 @@
+// Package httpauth provides authentication profile support for APIs that use
+// HTTP authentication schemes like Basic and Bearer, or other custom headers
+// built from profile values.
+package httpauth
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/danielgtaylor/openapi-cli-generator/cli"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// BasicHandler sets up HTTP Basic authentication using the profile's
+// `username` and `password`.
+type BasicHandler struct {
+	Keys []string
+}
+
+// ProfileKeys returns the key names for fields to store in the profile.
+func (h *BasicHandler) ProfileKeys() []string {
+	return append([]string{"username", "password"}, h.Keys...)
+}
+
+// OnRequest gets run before the request goes out on the wire.
+func (h *BasicHandler) OnRequest(log *zerolog.Logger, request *http.Request) error {
+	if request.Header.Get("Authorization") == "" {
+		profile, err := cli.LoadProfile()
+		if err != nil {
+			return err
+		}
+
+		request.SetBasicAuth(profile["username"], profile["password"])
+	}
+
+	return nil
+}
+
+// BearerHandler sets up authentication with a static bearer token from the
+// profile's `token`, e.g. a personal access token.
+type BearerHandler struct {
+	Keys []string
+}
+
+// ProfileKeys returns the key names for fields to store in the profile.
+func (h *BearerHandler) ProfileKeys() []string {
+	return append([]string{"token"}, h.Keys...)
+}
+
+// OnRequest gets run before the request goes out on the wire.
+func (h *BearerHandler) OnRequest(log *zerolog.Logger, request *http.Request) error {
+	if request.Header.Get("Authorization") == "" {
+		profile, err := cli.LoadProfile()
+		if err != nil {
+			return err
+		}
+
+		request.Header.Set("Authorization", "Bearer "+profile["token"])
+	}
+
+	return nil
+}
+
+// HeaderHandler sets any number of headers from profile values. Header values
+// are Go templates which are passed the profile, e.g. `{{ .tenant }}`.
+type HeaderHandler struct {
+	Headers map[string]string
+	Keys    []string
+}
+
+// ProfileKeys returns the key names for fields to store in the profile.
+func (h *HeaderHandler) ProfileKeys() []string {
+	return h.Keys
+}
+
+// OnRequest gets run before the request goes out on the wire.
+func (h *HeaderHandler) OnRequest(log *zerolog.Logger, request *http.Request) error {
+	profile, err := cli.LoadProfile()
+	if err != nil {
+		return err
+	}
+
+	for name, value := range h.Headers {
+		if request.Header.Get(name) != "" {
+			continue
+		}
+
+		tmpl, err := template.New(name).Option("missingkey=zero").Parse(value)
+		if err != nil {
+			return fmt.Errorf("invalid template for header %s: %v", name, err)
+		}
+
+		buf := &bytes.Buffer{}
+		if err := tmpl.Execute(buf, profile); err != nil {
+			return fmt.Errorf("invalid template for header %s: %v", name, err)
+		}
+
+		request.Header.Set(name, buf.String())
+	}
+
+	return nil
+}
+
+// ForScheme returns a handler for an HTTP authentication scheme as used in
+// OpenAPI security schemes of type `http`, or nil if it's not supported.
+func ForScheme(scheme string) cli.AuthHandler {
+	switch strings.ToLower(scheme) {
+	case "basic":
+		return &BasicHandler{}
+	case "bearer":
+		return &BearerHandler{}
+	}
+
+	return nil
+}
+
+// UseSchemes registers handlers for OpenAPI security schemes of type `http`,
+// given as a map of security scheme names to HTTP authentication schemes. If
+// only one scheme is supported, then it is registered without a type name.
+// Must be called *after* you have called `cli.Init()`.
+func UseSchemes(schemes map[string]string) {
+	names := []string{}
+	for name, scheme := range schemes {
+		if ForScheme(scheme) == nil {
+			log.Debug().Msgf("Unsupported HTTP auth scheme %s for %s", scheme, name)
+			continue
+		}
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	if len(names) == 1 {
+		cli.UseAuth("", ForScheme(schemes[names[0]]))
+		return
+	}
+
+	for _, name := range names {
+		cli.UseAuth(name, ForScheme(schemes[name]))
+	}
+}
+
+// InitBasic sets up HTTP Basic authentication. Must be called *after* you
+// have called `cli.Init()`. Passing `extra` values will set additional custom
+// keys to store for each profile.
+func InitBasic(extra ...string) {
+	cli.UseAuth("", &BasicHandler{Keys: extra})
+}
+
+// InitBearer sets up static bearer token authentication. Must be called
+// *after* you have called `cli.Init()`. Passing `extra` values will set
+// additional custom keys to store for each profile.
+func InitBearer(extra ...string) {
+	cli.UseAuth("", &BearerHandler{Keys: extra})
+}
+
+// InitHeaders sets up authentication using custom headers templated from the
+// given profile keys. Must be called *after* you have called `cli.Init()`.
+func InitHeaders(headers map[string]string, keys ...string) {
+	cli.UseAuth("", &HeaderHandler{Headers: headers, Keys: keys})
+}
@@ -0,0 +1,62 @@
+package httpauth
+
+import (
+	"testing"
+
+	"github.com/danielgtaylor/openapi-cli-generator/cli"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBasicAuth(t *testing.T) {
+	cli.Init(&cli.Config{
+		AppName:   "test",
+		EnvPrefix: "TEST",
+	})
+	InitBasic()
+	cli.Creds.Set("profiles.default.username", "user")
+	cli.Creds.Set("profiles.default.password", "pass")
+
+	r := cli.Client.Get()
+	r.Do()
+
+	assert.Equal(t, "Basic dXNlcjpwYXNz", r.Context.Request.Header.Get("Authorization"))
+}
+
+func TestBearerAuth(t *testing.T) {
+	cli.Init(&cli.Config{
+		AppName:   "test",
+		EnvPrefix: "TEST",
+	})
+	InitBearer()
+	cli.Creds.Set("profiles.default.token", "abc")
+
+	r := cli.Client.Get()
+	r.Do()
+
+	assert.Equal(t, "Bearer abc", r.Context.Request.Header.Get("Authorization"))
+}
+
+func TestHeaderAuth(t *testing.T) {
+	cli.Init(&cli.Config{
+		AppName:   "test",
+		EnvPrefix: "TEST",
+	})
+	InitHeaders(map[string]string{
+		"X-Tenant": "{{ .tenant }}",
+		"X-Key":    "key-{{ .key }}",
+	}, "tenant", "key")
+	cli.Creds.Set("profiles.default.tenant", "t1")
+	cli.Creds.Set("profiles.default.key", "abc")
+
+	r := cli.Client.Get()
+	r.Do()
+
+	assert.Equal(t, "t1", r.Context.Request.Header.Get("X-Tenant"))
+	assert.Equal(t, "key-abc", r.Context.Request.Header.Get("X-Key"))
+}
+
+func TestForScheme(t *testing.T) {
+	assert.IsType(t, &BasicHandler{}, ForScheme("Basic"))
+	assert.IsType(t, &BearerHandler{}, ForScheme("bearer"))
+	assert.Nil(t, ForScheme("digest"))
+}
@@ -28,6 +28,8 @@ const (
 	ExtIgnore      = "x-cli-ignore"
 	ExtHidden      = "x-cli-hidden"
 	ExtName        = "x-cli-name"
+	ExtOutput      = "x-cli-output"
+	ExtTable       = "x-cli-table"
 	ExtWaiters     = "x-cli-waiters"
 )
 
@@ -63,7 +65,8 @@ type Operation struct {
 	MediaType      string
 	Examples       []string
 	Hidden         bool
-	NeedsResponse  bool
+	OutputFormat   string
+	TableColumns   []string
 	Waiters        []*WaiterParams
 }
 
@@ -104,14 +107,15 @@ type WaiterParams struct {
 type Server struct {
 	Description string
 	URL         string
-	// TODO: handle server parameters
+	Variables   map[string]string
 }
 
 // Imports describe optional imports based on features in use.
 type Imports struct {
-	Fmt     bool
-	Strings bool
-	Time    bool
+	Fmt      bool
+	Strings  bool
+	Time     bool
+	HTTPAuth bool
 }
 
 // OpenAPI describes an API
@@ -123,6 +127,7 @@ type OpenAPI struct {
 	Title        string
 	Description  string
 	Servers      []*Server
+	HTTPAuth     map[string]string
 	Operations   []*Operation
 	Waiters      []*Waiter
 }
@@ -149,12 +154,34 @@ func ProcessAPI(shortName string, api *openapi3.Swagger) *OpenAPI {
 	}
 
 	for _, s := range api.Servers {
+		// Keep the default value for each server variable, e.g. `{region}`, so
+		// that it can be expanded or overridden at runtime.
+		variables := make(map[string]string)
+		for name, v := range s.Variables {
+			if v.Default != nil {
+				variables[name] = fmt.Sprintf("%v", v.Default)
+			}
+		}
+
 		result.Servers = append(result.Servers, &Server{
 			Description: s.Description,
 			URL:         s.URL,
+			Variables:   variables,
 		})
 	}
 
+	// Keep HTTP auth security schemes, e.g. `basic` or `bearer`, by name so
+	// that handlers can be registered for them.
+	for name, ref := range api.Components.SecuritySchemes {
+		if ref.Value != nil && ref.Value.Type == "http" {
+			if result.HTTPAuth == nil {
+				result.HTTPAuth = make(map[string]string)
+			}
+			result.HTTPAuth[name] = ref.Value.Scheme
+			result.Imports.HTTPAuth = true
+		}
+	}
+
 	// Convenience map for operation ID -> operation
 	operationMap := make(map[string]*Operation)
 
@@ -255,6 +282,16 @@ func ProcessAPI(shortName string, api *openapi3.Swagger) *OpenAPI {
 				json.Unmarshal(operation.Extensions[ExtHidden].(json.RawMessage), &hidden)
 			}
 
+			outputFormat := ""
+			if operation.Extensions[ExtOutput] != nil {
+				outputFormat = extStr(operation.Extensions[ExtOutput])
+			}
+
+			var tableColumns []string
+			if operation.Extensions[ExtTable] != nil {
+				json.Unmarshal(operation.Extensions[ExtTable].(json.RawMessage), &tableColumns)
+			}
+
 			returnType := "interface{}"
 		returnTypeLoop:
 			for code, ref := range operation.Responses {
@@ -297,6 +334,8 @@ func ProcessAPI(shortName string, api *openapi3.Swagger) *OpenAPI {
 				MediaType:      reqMt,
 				Examples:       examples,
 				Hidden:         hidden,
+				OutputFormat:   outputFormat,
+				TableColumns:   tableColumns,
 			}
 
 			operationMap[operation.OperationID] = o
@@ -353,7 +392,6 @@ func ProcessAPI(shortName string, api *openapi3.Swagger) *OpenAPI {
 					args = append(args, selector)
 
 					result.Imports.Fmt = true
-					op.NeedsResponse = true
 				}
 
 				// Transform from OpenAPI param names to CLI names
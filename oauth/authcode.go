@@ -1,10 +1,14 @@
 package oauth
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"html"
+	"net"
 	"net/http"
 	"net/url"
 	"os/exec"
@@ -12,8 +16,6 @@ import (
 	"strings"
 	"time"
 
-	"context"
-
 	"github.com/danielgtaylor/openapi-cli-generator/cli"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -39,101 +41,259 @@ func open(url string) error {
 	return exec.Command(cmd, args...).Start()
 }
 
-// authHandler is an HTTP handler that takes a channel and sends the `code`
-// query param when it gets a request.
+// DefaultRedirectPort is the loopback port used to receive the authorization
+// code redirect unless another is configured.
+const DefaultRedirectPort = 8484
+
+// RandomPort can be used as a redirect port to pick any free port. The
+// provider must allow any port for loopback redirects, as described in
+// https://tools.ietf.org/html/rfc8252#section-7.3.
+const RandomPort = -1
+
+// DefaultLoginTimeout is how long to wait for the user to log in.
+const DefaultLoginTimeout = 5 * time.Minute
+
+// callbackResult is the outcome of the redirect back from the authorization
+// server.
+type callbackResult struct {
+	code string
+	err  error
+}
+
+// authHandler is an HTTP handler that validates the redirect from the
+// authorization server and sends the resulting `code` or error on a channel.
 type authHandler struct {
-	c chan string
+	c     chan callbackResult
+	state string
 }
 
-func (h authHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	h.c <- r.URL.Query().Get("code")
+// page writes a simple HTML page with a message for the user.
+func page(w http.ResponseWriter, status int, message string) {
 	w.Header().Set("Content-Type", "text/html")
-	w.Write([]byte("<html><body><p>Login successful. Please return to the terminal. You may now close this window.</p></body></html>"))
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<html><body><p>%s</p></body></html>", html.EscapeString(message))
+}
+
+func (h authHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	if r.URL.Path != "/" || (query.Get("code") == "" && query.Get("error") == "") {
+		// Ignore other requests, like the browser asking for a favicon.
+		http.NotFound(w, r)
+		return
+	}
+
+	var result callbackResult
+	switch {
+	case query.Get("state") != h.state:
+		// Possible cross-site request forgery, so don't use this code.
+		result.err = errors.New("login failed: invalid state parameter in redirect")
+	case query.Get("error") != "":
+		result.err = fmt.Errorf("login failed: %s", query.Get("error"))
+		if description := query.Get("error_description"); description != "" {
+			result.err = fmt.Errorf("%v: %s", result.err, description)
+		}
+	default:
+		result.code = query.Get("code")
+	}
+
+	if result.err != nil {
+		page(w, http.StatusBadRequest, result.err.Error()+". Please return to the terminal.")
+	} else {
+		page(w, http.StatusOK, "Login successful. Please return to the terminal. You may now close this window.")
+	}
+
+	// Only the first result is used, so never block on later requests.
+	select {
+	case h.c <- result:
+	default:
+	}
+}
+
+// randomString returns a URL-safe random string from n random bytes.
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
 }
 
 // AuthorizationCodeTokenSource with PKCE as described in:
 // https://www.oauth.com/oauth2-servers/pkce/
-// This works by running a local HTTP server on port 8484 and then having the
-// user log in through a web browser, which redirects to the local server with
-// an authorization code. That code is then used to make another HTTP request
-// to fetch an auth token (and refresh token). That token is then in turn
-// used to make requests against the API.
+// This works by running a local HTTP server on a loopback port and then
+// having the user log in through a web browser, which redirects to the local
+// server with an authorization code. That code is then used to make another
+// HTTP request to fetch an auth token (and refresh token). That token is then
+// in turn used to make requests against the API.
 type AuthorizationCodeTokenSource struct {
 	ClientID       string
 	AuthorizeURL   string
 	TokenURL       string
 	EndpointParams *url.Values
 	Scopes         []string
+
+	// CodeChallengeMethod is the PKCE method, either `S256` (the default) or
+	// `plain` for providers which don't support hashing.
+	CodeChallengeMethod string
+
+	// RedirectPort is the loopback port to receive the redirect on. Defaults
+	// to `DefaultRedirectPort`. Use `RandomPort` to pick any free port.
+	RedirectPort int
+
+	// LoginTimeout is how long to wait for the user to log in. Defaults to
+	// `DefaultLoginTimeout`.
+	LoginTimeout time.Duration
+
+	// NoBrowser prints the login URL without trying to open a browser.
+	NoBrowser bool
 }
 
 // Token generates a new token using an authorization code.
 func (ac *AuthorizationCodeTokenSource) Token() (*oauth2.Token, error) {
 	// Generate a random code verifier string
-	verifierBytes := make([]byte, 32)
-	if _, err := rand.Read(verifierBytes); err != nil {
+	verifier, err := randomString(32)
+	if err != nil {
 		return nil, err
 	}
 
-	verifier := base64.RawURLEncoding.EncodeToString(verifierBytes)
+	// Generate a random state to protect against cross-site request forgery.
+	state, err := randomString(16)
+	if err != nil {
+		return nil, err
+	}
 
 	// Generate a code challenge. Only the challenge is sent when requesting a
 	// code which allows us to keep it secret for now.
-	shaBytes := sha256.Sum256([]byte(verifier))
-	challenge := base64.RawURLEncoding.EncodeToString(shaBytes[:])
+	method := ac.CodeChallengeMethod
+	challenge := verifier
+	if method != "plain" {
+		method = "S256"
+		shaBytes := sha256.Sum256([]byte(verifier))
+		challenge = base64.RawURLEncoding.EncodeToString(shaBytes[:])
+	}
 
-	// Generate a URL with the challenge to have the user log in.
-	url := fmt.Sprintf("%s?response_type=code&code_challenge=%s&code_challenge_method=S256&client_id=%s&redirect_uri=http://localhost:8484/&scope=%s", ac.AuthorizeURL, challenge, ac.ClientID, strings.Join(ac.Scopes, `%20`))
+	// Listen before opening the user's browser so we are ready for any
+	// redirect. Only the loopback interface is used so that other machines on
+	// the network can't send us a code.
+	port := ac.RedirectPort
+	switch port {
+	case 0:
+		port = DefaultRedirectPort
+	case RandomPort:
+		port = 0
+	}
 
-	if len(*ac.EndpointParams) > 0 {
-		url += "&" + ac.EndpointParams.Encode()
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return nil, fmt.Errorf("could not listen for login redirect: %v", err)
 	}
 
-	// Run server before opening the user's browser so we are ready for any redirect.
-	codeChan := make(chan string)
-	handler := authHandler{
-		c: codeChan,
+	// Only listen on the loopback interface, but keep sending `localhost` since
+	// that is what most providers have registered as the redirect URI.
+	redirectURI := fmt.Sprintf("http://localhost:%d/", listener.Addr().(*net.TCPAddr).Port)
+
+	// Generate a URL with the challenge to have the user log in.
+	params := url.Values{}
+	if ac.EndpointParams != nil {
+		for k, v := range *ac.EndpointParams {
+			params[k] = v
+		}
+	}
+	params.Set("response_type", "code")
+	params.Set("code_challenge", challenge)
+	params.Set("code_challenge_method", method)
+	params.Set("client_id", ac.ClientID)
+	params.Set("redirect_uri", redirectURI)
+	params.Set("state", state)
+	params.Set("scope", strings.Join(ac.Scopes, " "))
+
+	separator := "?"
+	if strings.Contains(ac.AuthorizeURL, "?") {
+		separator = "&"
 	}
+	authURL := ac.AuthorizeURL + separator + params.Encode()
 
+	results := make(chan callbackResult, 1)
 	s := &http.Server{
-		Addr:           ":8484",
-		Handler:        handler,
+		Handler: authHandler{
+			c:     results,
+			state: state,
+		},
 		ReadTimeout:    5 * time.Second,
 		WriteTimeout:   5 * time.Second,
 		MaxHeaderBytes: 1024,
 	}
 
-	go func() {
-		// Run in a goroutine until the server is closed or we get an error.
-		if err := s.ListenAndServe(); err != http.ErrServerClosed {
-			log.Fatal().Err(err).Msg("Server exited unexpectedly")
+	go s.Serve(listener)
+	defer s.Shutdown(context.Background())
+
+	// Print the URL for manual use in case opening a browser fails.
+	fmt.Fprintln(cli.Stderr, "Open your browser to log in using the URL:")
+	fmt.Fprintln(cli.Stderr, authURL)
+
+	if !ac.NoBrowser {
+		if err := open(authURL); err != nil {
+			log.Debug().Err(err).Msg("Could not open browser")
 		}
-	}()
+	}
+
+	timeout := ac.LoginTimeout
+	if timeout == 0 {
+		timeout = DefaultLoginTimeout
+	}
+
+	ctx := cli.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
 
-	// Open auth URL in browser, print for manual use in case open fails.
-	fmt.Println("Open your browser to log in using the URL:")
-	fmt.Println(url)
-	open(url)
+	// Wait for the redirect with the code, then exchange it for a token.
+	var result callbackResult
+	select {
+	case result = <-results:
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("timed out after %v waiting for login", timeout)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
 
-	// Get code from handler, exchange it for a token, and then return it. This
-	// channel read blocks until one code becomes available.
-	// There is currently no timeout.
-	code := <-codeChan
-	s.Shutdown(context.Background())
+	if result.err != nil {
+		return nil, result.err
+	}
 
-	payload := fmt.Sprintf("grant_type=authorization_code&client_id=%s&code_verifier=%s&code=%s&redirect_uri=http://localhost:8484/", ac.ClientID, verifier, code)
+	payload := url.Values{}
+	payload.Set("grant_type", "authorization_code")
+	payload.Set("client_id", ac.ClientID)
+	payload.Set("code_verifier", verifier)
+	payload.Set("code", result.code)
+	payload.Set("redirect_uri", redirectURI)
 
-	return requestToken(ac.TokenURL, payload)
+	return requestToken(ac.TokenURL, payload.Encode())
 }
 
 // AuthCodeHandler sets up the OAuth 2.0 authorization code with PKCE authentication
-// flow.
+// flow. If `Issuer` is set, then any missing URLs and the PKCE method are
+// discovered from the issuer's OpenID Connect configuration.
 type AuthCodeHandler struct {
-	ClientID     string
-	AuthorizeURL string
-	TokenURL     string
-	Keys         []string
-	Params       []string
-	Scopes       []string
+	ClientID      string
+	Issuer        string
+	AuthorizeURL  string
+	TokenURL      string
+	RevocationURL string
+	Keys          []string
+	Params        []string
+	Scopes        []string
+
+	// RedirectPort is the loopback port to receive the login redirect on.
+	// Defaults to `DefaultRedirectPort`. Use `RandomPort` to pick any free
+	// port if the provider allows it.
+	RedirectPort int
+
+	// LoginTimeout is how long to wait for the user to log in. Defaults to
+	// `DefaultLoginTimeout`.
+	LoginTimeout time.Duration
 
 	getParamsFunc func(profile map[string]string) url.Values
 }
@@ -150,6 +310,15 @@ func (h *AuthCodeHandler) OnRequest(log *zerolog.Logger, request *http.Request)
 		// or generate a new one from the issuing server.
 		profile := cli.GetProfile()
 
+		authorizeURL, tokenURL := h.AuthorizeURL, h.TokenURL
+		metadata, err := discoverEndpoints(h.Issuer, map[string]*string{
+			"authorization_endpoint": &authorizeURL,
+			"token_endpoint":         &tokenURL,
+		})
+		if err != nil {
+			return err
+		}
+
 		params := url.Values{}
 		if h.getParamsFunc != nil {
 			// Backward-compatibility with old call style, only used internally.
@@ -161,10 +330,17 @@ func (h *AuthCodeHandler) OnRequest(log *zerolog.Logger, request *http.Request)
 
 		source := &AuthorizationCodeTokenSource{
 			ClientID:       h.ClientID,
-			AuthorizeURL:   h.AuthorizeURL,
-			TokenURL:       h.TokenURL,
+			AuthorizeURL:   authorizeURL,
+			TokenURL:       tokenURL,
 			EndpointParams: &params,
 			Scopes:         h.Scopes,
+			RedirectPort:   h.RedirectPort,
+			LoginTimeout:   h.LoginTimeout,
+			NoBrowser:      viper.GetBool("no-browser"),
+		}
+
+		if metadata != nil {
+			source.CodeChallengeMethod = metadata.codeChallengeMethod()
 		}
 
 		// Try to get a cached refresh token from the current profile and use
@@ -172,7 +348,7 @@ func (h *AuthCodeHandler) OnRequest(log *zerolog.Logger, request *http.Request)
 		refreshKey := "profiles." + viper.GetString("profile") + ".refresh"
 		refreshSource := RefreshTokenSource{
 			ClientID:       h.ClientID,
-			TokenURL:       h.TokenURL,
+			TokenURL:       tokenURL,
 			EndpointParams: &params,
 			RefreshToken:   cli.Cache.GetString(refreshKey),
 			TokenSource:    source,
@@ -204,6 +380,8 @@ func InitAuthCode(clientID string, authorizeURL string, tokenURL string, options
 		TokenURL:     tokenURL,
 		Scopes:       c.scopes,
 		Keys:         c.extra,
+		RedirectPort: c.redirectPort,
+		LoginTimeout: c.loginTimeout,
 
 		// Since you can pass a function to get params, we can't use the normal
 		// preset `Params` field. We use an internal field here for backwards
@@ -0,0 +1,101 @@
+package oauth
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/openapi-cli-generator/cli"
+	"github.com/danielgtaylor/openapi-cli-generator/cli/clitest"
+	"github.com/stretchr/testify/assert"
+)
+
+// login runs the auth code flow, calling the redirect function with the
+// printed login URL once the local server is ready.
+func login(t *testing.T, source *AuthorizationCodeTokenSource, redirect func(authURL *url.URL)) (string, error) {
+	r, w := io.Pipe()
+	cli.Stderr = w
+
+	go func() {
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			if strings.HasPrefix(scanner.Text(), "http") {
+				authURL, err := url.Parse(scanner.Text())
+				assert.NoError(t, err)
+				redirect(authURL)
+			}
+		}
+	}()
+
+	token, err := source.Token()
+	w.Close()
+
+	if err != nil {
+		return "", err
+	}
+
+	return token.AccessToken, nil
+}
+
+func TestAuthCodeTokenSource(t *testing.T) {
+	clitest.Setup(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		assert.Equal(t, "code1", r.PostForm.Get("code"))
+		w.Write([]byte(`{"access_token": "abc", "token_type": "Bearer", "expires_in": 3600}`))
+	}))
+	defer server.Close()
+
+	source := &AuthorizationCodeTokenSource{
+		ClientID:     "id1",
+		AuthorizeURL: server.URL + "/authorize",
+		TokenURL:     server.URL + "/token",
+		RedirectPort: RandomPort,
+		LoginTimeout: time.Second,
+		NoBrowser:    true,
+		Scopes:       []string{"openid", "offline_access"},
+		EndpointParams: &url.Values{
+			"audience": {"https://api.example.com/?a=1&b=2"},
+		},
+	}
+
+	callback := func(query string) func(authURL *url.URL) {
+		return func(authURL *url.URL) {
+			redirectURI := authURL.Query().Get("redirect_uri")
+			state := authURL.Query().Get("state")
+			assert.NotEmpty(t, state)
+			assert.NotContains(t, redirectURI, ":8484")
+			assert.True(t, strings.HasPrefix(redirectURI, "http://localhost:"))
+			assert.Equal(t, "openid offline_access", authURL.Query().Get("scope"))
+			assert.Equal(t, "https://api.example.com/?a=1&b=2", authURL.Query().Get("audience"))
+			assert.Equal(t, "id1", authURL.Query().Get("client_id"))
+
+			// Unrelated requests are ignored.
+			res, err := http.Get(redirectURI + "favicon.ico")
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusNotFound, res.StatusCode)
+
+			http.Get(redirectURI + "?" + strings.Replace(query, "STATE", state, -1))
+		}
+	}
+
+	token, err := login(t, source, callback("code=code1&state=STATE"))
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", token)
+
+	_, err = login(t, source, callback("code=code1&state=forged"))
+	assert.EqualError(t, err, "login failed: invalid state parameter in redirect")
+
+	_, err = login(t, source, callback("error=access_denied&error_description=User+cancelled&state=STATE"))
+	assert.EqualError(t, err, "login failed: access_denied: User cancelled")
+
+	source.LoginTimeout = 10 * time.Millisecond
+	_, err = login(t, source, func(authURL *url.URL) {})
+	assert.EqualError(t, err, "timed out after 10ms waiting for login")
+}
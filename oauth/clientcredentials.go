@@ -1,6 +1,7 @@
 package oauth
 
 import (
+	"context"
 	"net/http"
 	"net/url"
 
@@ -20,12 +21,16 @@ func NewClientCredentialsHandler(tokenURL string, keys, params, scopes []string)
 	}
 }
 
-// ClientCredentialsHandler implements the Client Credentials OAuth2 flow.
+// ClientCredentialsHandler implements the Client Credentials OAuth2 flow. If
+// `Issuer` is set and `TokenURL` is empty, then the token URL is discovered
+// from the issuer's OpenID Connect configuration.
 type ClientCredentialsHandler struct {
-	TokenURL string
-	Keys     []string
-	Params   []string
-	Scopes   []string
+	Issuer        string
+	TokenURL      string
+	RevocationURL string
+	Keys          []string
+	Params        []string
+	Scopes        []string
 
 	getParamsFunc func(profile map[string]string) url.Values
 }
@@ -50,6 +55,13 @@ func (h *ClientCredentialsHandler) OnRequest(log *zerolog.Logger, request *http.
 			return ErrInvalidProfile
 		}
 
+		tokenURL := h.TokenURL
+		if _, err := discoverEndpoints(h.Issuer, map[string]*string{
+			"token_endpoint": &tokenURL,
+		}); err != nil {
+			return err
+		}
+
 		params := url.Values{}
 		if h.getParamsFunc != nil {
 			// Backward-compatibility with old call style, only used internally.
@@ -59,13 +71,19 @@ func (h *ClientCredentialsHandler) OnRequest(log *zerolog.Logger, request *http.
 			params.Add(name, profile[name])
 		}
 
+		// Use the CLI's TLS and proxy settings to get tokens.
+		client, err := cli.HTTPClient()
+		if err != nil {
+			return err
+		}
+
 		source := (&clientcredentials.Config{
 			ClientID:       profile["client_id"],
 			ClientSecret:   profile["client_secret"],
-			TokenURL:       h.TokenURL,
+			TokenURL:       tokenURL,
 			EndpointParams: params,
 			Scopes:         h.Scopes,
-		}).TokenSource(oauth2.NoContext)
+		}).TokenSource(context.WithValue(cli.Context, oauth2.HTTPClient, client))
 
 		return TokenHandler(source, log, request)
 	}
@@ -95,9 +113,4 @@ func InitClientCredentials(tokenURL string, options ...func(*config) error) {
 	handler.getParamsFunc = c.getParams
 
 	cli.UseAuth("", handler)
-
-	// TODO: retry on 401
-	// cli.Client.UseResponse(func(ctx *context.Context, h context.Handler) {
-	// 	h.Next(ctx)
-	// })
 }
@@ -0,0 +1,39 @@
+package oauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielgtaylor/openapi-cli-generator/cli"
+	"github.com/danielgtaylor/openapi-cli-generator/cli/clitest"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientCredentialsUsesTransport(t *testing.T) {
+	clitest.Setup(t)
+	viper.Set("profile", "default")
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token": "abc", "token_type": "bearer", "expires_in": 3600}`))
+	}))
+	defer server.Close()
+
+	handler := &ClientCredentialsHandler{TokenURL: server.URL}
+	cli.UseAuth("", handler)
+	cli.Creds.Set("profiles.default.client_id", "id1")
+	cli.Creds.Set("profiles.default.client_secret", "secret1")
+
+	// The token server's certificate is self-signed, so this only works if the
+	// CLI's TLS settings are used.
+	viper.Set("insecure", true)
+	defer viper.Set("insecure", false)
+
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com/", nil)
+	log := zerolog.Nop()
+	assert.NoError(t, handler.OnRequest(&log, req))
+	assert.Equal(t, "Bearer abc", req.Header.Get("Authorization"))
+}
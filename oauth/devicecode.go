@@ -0,0 +1,243 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/openapi-cli-generator/cli"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+	"golang.org/x/oauth2"
+)
+
+// intervalUnit is the unit of the polling interval and expiration returned by
+// the device authorization endpoint. It is only changed by tests.
+var intervalUnit = time.Second
+
+// deviceCodeResponse is returned from the device authorization endpoint as
+// described in https://tools.ietf.org/html/rfc8628#section-3.2. Some
+// providers use `verification_url` instead of `verification_uri`.
+type deviceCodeResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURL         string `json:"verification_url"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// DeviceCodeTokenSource implements the OAuth 2.0 Device Authorization Grant
+// described in https://tools.ietf.org/html/rfc8628. It prints a code and URL
+// for the user to visit on any device with a browser, then polls the token
+// endpoint until the user has logged in. This works over SSH and in
+// containers, where opening a local browser is not possible.
+type DeviceCodeTokenSource struct {
+	ClientID       string
+	DeviceURL      string
+	TokenURL       string
+	EndpointParams *url.Values
+	Scopes         []string
+}
+
+// requestDeviceCode starts the flow by getting a device and user code.
+func (dc *DeviceCodeTokenSource) requestDeviceCode() (*deviceCodeResponse, error) {
+	params := url.Values{}
+	if dc.EndpointParams != nil {
+		for k, v := range *dc.EndpointParams {
+			params[k] = v
+		}
+	}
+	params.Set("client_id", dc.ClientID)
+	if len(dc.Scopes) > 0 {
+		params.Set("scope", strings.Join(dc.Scopes, " "))
+	}
+
+	log.Debug().Str("url", dc.DeviceURL).Msg("Requesting device code")
+	req, err := http.NewRequest(http.MethodPost, dc.DeviceURL, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("content-type", "application/x-www-form-urlencoded")
+
+	client, err := cli.HTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+	body, _ := ioutil.ReadAll(res.Body)
+
+	if res.StatusCode > 200 {
+		return nil, fmt.Errorf("bad response from device authorization endpoint:\n%s", body)
+	}
+
+	decoded := &deviceCodeResponse{}
+	if err := json.Unmarshal(body, decoded); err != nil {
+		return nil, err
+	}
+
+	if decoded.VerificationURI == "" {
+		decoded.VerificationURI = decoded.VerificationURL
+	}
+
+	if decoded.DeviceCode == "" || decoded.VerificationURI == "" {
+		return nil, fmt.Errorf("invalid response from device authorization endpoint:\n%s", body)
+	}
+
+	return decoded, nil
+}
+
+// Token generates a new token using a device code.
+func (dc *DeviceCodeTokenSource) Token() (*oauth2.Token, error) {
+	device, err := dc.requestDeviceCode()
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(cli.Stderr, "To log in, open %s and enter the code: %s\n", device.VerificationURI, device.UserCode)
+	if device.VerificationURIComplete != "" {
+		fmt.Fprintf(cli.Stderr, "Or open this URL to enter the code automatically:\n%s\n", device.VerificationURIComplete)
+	}
+
+	// Default values from the spec if the server doesn't provide them.
+	interval := 5 * intervalUnit
+	if device.Interval > 0 {
+		interval = time.Duration(device.Interval) * intervalUnit
+	}
+
+	expiresIn := 30 * 60 * intervalUnit
+	if device.ExpiresIn > 0 {
+		expiresIn = time.Duration(device.ExpiresIn) * intervalUnit
+	}
+
+	ctx := cli.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	deadline := time.Now().Add(expiresIn)
+
+	payload := url.Values{}
+	payload.Set("grant_type", "urn:ietf:params:oauth:grant-type:device_code")
+	payload.Set("device_code", device.DeviceCode)
+	payload.Set("client_id", dc.ClientID)
+
+	for {
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		token, err := requestToken(dc.TokenURL, payload.Encode())
+		if err == nil {
+			return token, nil
+		}
+
+		tokenErr, ok := err.(*tokenError)
+		if !ok {
+			return nil, err
+		}
+
+		switch tokenErr.Code {
+		case "authorization_pending":
+			// The user hasn't finished logging in yet.
+		case "slow_down":
+			// Polling too quickly, so increase the interval as required by
+			// https://tools.ietf.org/html/rfc8628#section-3.5.
+			interval += 5 * intervalUnit
+		case "access_denied":
+			return nil, errors.New("login was denied")
+		case "expired_token":
+			return nil, errors.New("device code expired before login completed")
+		default:
+			return nil, err
+		}
+
+		if time.Now().After(deadline) {
+			return nil, errors.New("device code expired before login completed")
+		}
+	}
+}
+
+// DeviceCodeHandler sets up the OAuth 2.0 device authorization grant
+// authentication flow. If `Issuer` is set, then any missing URLs are
+// discovered from the issuer's OpenID Connect configuration.
+type DeviceCodeHandler struct {
+	ClientID      string
+	Issuer        string
+	DeviceURL     string
+	TokenURL      string
+	RevocationURL string
+	Keys          []string
+	Params        []string
+	Scopes        []string
+}
+
+// ProfileKeys returns the key names for fields to store in the profile.
+func (h *DeviceCodeHandler) ProfileKeys() []string {
+	return h.Keys
+}
+
+// OnRequest gets run before the request goes out on the wire.
+func (h *DeviceCodeHandler) OnRequest(log *zerolog.Logger, request *http.Request) error {
+	if request.Header.Get("Authorization") == "" {
+		// No auth is set, so let's get the token either from a cache
+		// or generate a new one from the issuing server.
+		profile, err := cli.LoadProfile()
+		if err != nil {
+			return err
+		}
+
+		deviceURL, tokenURL := h.DeviceURL, h.TokenURL
+		if _, err := discoverEndpoints(h.Issuer, map[string]*string{
+			"device_authorization_endpoint": &deviceURL,
+			"token_endpoint":                &tokenURL,
+		}); err != nil {
+			return err
+		}
+
+		params := url.Values{}
+		for _, name := range h.Params {
+			params.Add(name, profile[name])
+		}
+
+		source := &DeviceCodeTokenSource{
+			ClientID:       h.ClientID,
+			DeviceURL:      deviceURL,
+			TokenURL:       tokenURL,
+			EndpointParams: &params,
+			Scopes:         h.Scopes,
+		}
+
+		// Try to get a cached refresh token from the current profile and use
+		// it to wrap the device code token source with a refreshing source.
+		refreshKey := "profiles." + viper.GetString("profile") + ".refresh"
+		refreshSource := RefreshTokenSource{
+			ClientID:       h.ClientID,
+			TokenURL:       tokenURL,
+			EndpointParams: &params,
+			RefreshToken:   cli.Cache.GetString(refreshKey),
+			TokenSource:    source,
+		}
+
+		return TokenHandler(refreshSource, log, request)
+	}
+
+	return nil
+}
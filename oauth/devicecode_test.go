@@ -0,0 +1,79 @@
+package oauth
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/openapi-cli-generator/cli"
+	"github.com/danielgtaylor/openapi-cli-generator/cli/clitest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeviceCodeTokenSource(t *testing.T) {
+	clitest.Setup(t)
+
+	intervalUnit = time.Millisecond
+	defer func() { intervalUnit = time.Second }()
+
+	stderr := &bytes.Buffer{}
+	cli.Stderr = stderr
+
+	polls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		assert.Equal(t, "id1", r.PostForm.Get("client_id"))
+		assert.Equal(t, "openid offline_access", r.PostForm.Get("scope"))
+		assert.Equal(t, "api", r.PostForm.Get("audience"))
+
+		w.Write([]byte(`{"device_code": "dev1", "user_code": "ABCD-EFGH", "verification_uri": "https://example.com/activate", "expires_in": 1000, "interval": 1}`))
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		assert.Equal(t, "urn:ietf:params:oauth:grant-type:device_code", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "dev1", r.PostForm.Get("device_code"))
+
+		polls++
+		switch polls {
+		case 1:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": "authorization_pending"}`))
+		case 2:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": "slow_down"}`))
+		default:
+			w.Write([]byte(`{"access_token": "abc", "token_type": "Bearer", "expires_in": 3600}`))
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	source := &DeviceCodeTokenSource{
+		ClientID:       "id1",
+		DeviceURL:      server.URL + "/device",
+		TokenURL:       server.URL + "/token",
+		EndpointParams: &url.Values{"audience": []string{"api"}},
+		Scopes:         []string{"openid", "offline_access"},
+	}
+
+	token, err := source.Token()
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", token.AccessToken)
+	assert.Equal(t, 3, polls)
+	assert.Contains(t, stderr.String(), "https://example.com/activate")
+	assert.Contains(t, stderr.String(), "ABCD-EFGH")
+
+	mux.HandleFunc("/denied", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error": "access_denied"}`))
+	})
+
+	source.TokenURL = server.URL + "/denied"
+	_, err = source.Token()
+	assert.EqualError(t, err, "login was denied")
+}
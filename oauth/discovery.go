@@ -0,0 +1,173 @@
+package oauth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/openapi-cli-generator/cli"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+)
+
+// discoveryTTL is how long discovered provider metadata is cached.
+const discoveryTTL = 24 * time.Hour
+
+// ProviderMetadata describes an OpenID Connect provider's endpoints and
+// capabilities as returned from its `/.well-known/openid-configuration`
+// document. See https://openid.net/specs/openid-connect-discovery-1_0.html.
+type ProviderMetadata struct {
+	Issuer                        string   `json:"issuer"`
+	AuthorizationEndpoint         string   `json:"authorization_endpoint"`
+	TokenEndpoint                 string   `json:"token_endpoint"`
+	DeviceAuthorizationEndpoint   string   `json:"device_authorization_endpoint,omitempty"`
+	RevocationEndpoint            string   `json:"revocation_endpoint,omitempty"`
+	JWKSURI                       string   `json:"jwks_uri,omitempty"`
+	ScopesSupported               []string `json:"scopes_supported,omitempty"`
+	CodeChallengeMethodsSupported []string `json:"code_challenge_methods_supported,omitempty"`
+}
+
+// codeChallengeMethod returns the PKCE method to use, preferring `S256`. If
+// the provider doesn't advertise its supported methods, `S256` is assumed.
+func (m *ProviderMetadata) codeChallengeMethod() string {
+	for _, method := range m.CodeChallengeMethodsSupported {
+		if method == "S256" {
+			return method
+		}
+	}
+
+	for _, method := range m.CodeChallengeMethodsSupported {
+		if method == "plain" {
+			return method
+		}
+	}
+
+	return "S256"
+}
+
+// discoveryKey returns the cache key prefix for an issuer. Issuer URLs contain
+// dots, which Viper would treat as nested keys, so a hash is used instead.
+func discoveryKey(issuer string) string {
+	sum := sha256.Sum256([]byte(issuer))
+	return "discovery." + hex.EncodeToString(sum[:8])
+}
+
+// fetchMetadata loads the provider metadata from the issuer.
+func fetchMetadata(issuer string) (map[string]interface{}, *ProviderMetadata, error) {
+	uri := issuer + "/.well-known/openid-configuration"
+	log.Debug().Str("url", uri).Msg("Fetching OpenID configuration")
+
+	client, err := cli.HTTPClient()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res, err := client.Get(uri)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer res.Body.Close()
+	body, _ := ioutil.ReadAll(res.Body)
+
+	if res.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("bad response from %s:\n%s", uri, body)
+	}
+
+	raw := make(map[string]interface{})
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, nil, err
+	}
+
+	metadata := &ProviderMetadata{}
+	if err := json.Unmarshal(body, metadata); err != nil {
+		return nil, nil, err
+	}
+
+	// The spec requires the issuer to match exactly, but providers like Auth0
+	// are commonly configured with a trailing slash, so ignore it.
+	if strings.TrimSuffix(metadata.Issuer, "/") != issuer {
+		return nil, nil, fmt.Errorf("issuer %s does not match %s", metadata.Issuer, issuer)
+	}
+
+	return raw, metadata, nil
+}
+
+// Discover returns the OpenID Connect provider metadata for an issuer URL,
+// e.g. `https://example.okta.com` or
+// `https://login.microsoftonline.com/{tenant}/v2.0`. Results are stored in the
+// CLI cache for a day. If the issuer can't be reached, then stale cached
+// metadata is used when available.
+func Discover(issuer string) (*ProviderMetadata, error) {
+	issuer = strings.TrimSuffix(issuer, "/")
+	key := discoveryKey(issuer)
+
+	var cached *ProviderMetadata
+	if raw := cli.Cache.Get(key + ".metadata"); raw != nil {
+		data, _ := json.Marshal(raw)
+		decoded := &ProviderMetadata{}
+		if json.Unmarshal(data, decoded) == nil {
+			cached = decoded
+		}
+	}
+
+	if cached != nil && time.Since(cli.Cache.GetTime(key+".fetched")) < discoveryTTL {
+		return cached, nil
+	}
+
+	raw, metadata, err := fetchMetadata(issuer)
+	if err != nil {
+		if cached != nil {
+			log.Warn().Err(err).Msg("Using stale OpenID configuration")
+			return cached, nil
+		}
+		return nil, err
+	}
+
+	err = cli.UpdateCache(func(cache *viper.Viper) {
+		cache.Set(key+".issuer", issuer)
+		cache.Set(key+".fetched", time.Now())
+		cache.Set(key+".metadata", raw)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
+
+// discoverEndpoints fills in any empty endpoint URLs, keyed by their metadata
+// names, from the issuer's metadata. Returns nil if there is no issuer.
+func discoverEndpoints(issuer string, endpoints map[string]*string) (*ProviderMetadata, error) {
+	if issuer == "" {
+		return nil, nil
+	}
+
+	metadata, err := Discover(issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	discovered := map[string]string{
+		"authorization_endpoint":        metadata.AuthorizationEndpoint,
+		"token_endpoint":                metadata.TokenEndpoint,
+		"device_authorization_endpoint": metadata.DeviceAuthorizationEndpoint,
+		"revocation_endpoint":           metadata.RevocationEndpoint,
+	}
+
+	for name, value := range endpoints {
+		if *value == "" {
+			if discovered[name] == "" {
+				return nil, fmt.Errorf("issuer %s does not provide a %s", issuer, name)
+			}
+			*value = discovered[name]
+		}
+	}
+
+	return metadata, nil
+}
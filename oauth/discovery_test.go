@@ -0,0 +1,62 @@
+package oauth
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielgtaylor/openapi-cli-generator/cli"
+	"github.com/danielgtaylor/openapi-cli-generator/cli/clitest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscover(t *testing.T) {
+	clitest.Setup(t)
+
+	requests := 0
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Contains(t, r.URL.Path, "/.well-known/openid-configuration")
+		fmt.Fprintf(w, `{
+			"issuer": "%[1]s/tenant",
+			"authorization_endpoint": "%[1]s/tenant/authorize",
+			"token_endpoint": "%[1]s/tenant/token",
+			"code_challenge_methods_supported": ["plain"]
+		}`, server.URL)
+	}))
+	defer server.Close()
+
+	metadata, err := Discover(server.URL + "/tenant/")
+	assert.NoError(t, err)
+	assert.Equal(t, server.URL+"/tenant/token", metadata.TokenEndpoint)
+	assert.Equal(t, "plain", metadata.codeChallengeMethod())
+
+	// Results come from the cache, even after reloading it.
+	cli.ReloadCache()
+	metadata, err = Discover(server.URL + "/tenant")
+	assert.NoError(t, err)
+	assert.Equal(t, server.URL+"/tenant/authorize", metadata.AuthorizationEndpoint)
+	assert.Equal(t, 1, requests)
+
+	// Explicit URLs are kept and missing ones are filled in.
+	authorizeURL, tokenURL := "https://example.com/authorize", ""
+	_, err = discoverEndpoints(server.URL+"/tenant", map[string]*string{
+		"authorization_endpoint": &authorizeURL,
+		"token_endpoint":         &tokenURL,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com/authorize", authorizeURL)
+	assert.Equal(t, server.URL+"/tenant/token", tokenURL)
+
+	deviceURL := ""
+	_, err = discoverEndpoints(server.URL+"/tenant", map[string]*string{
+		"device_authorization_endpoint": &deviceURL,
+	})
+	assert.Error(t, err)
+
+	// The issuer in the document must match.
+	_, err = Discover(server.URL + "/tenant/other")
+	assert.Error(t, err)
+}
@@ -0,0 +1,312 @@
+package oauth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/openapi-cli-generator/cli"
+	"github.com/rs/zerolog"
+	"golang.org/x/oauth2"
+)
+
+// JWTBearerGrant is the grant type to exchange a signed JWT assertion for a
+// token as described in https://tools.ietf.org/html/rfc7523#section-2.1.
+const JWTBearerGrant = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+
+// clientAssertionType is used to authenticate a client with a signed JWT
+// (`private_key_jwt`) as described in
+// https://tools.ietf.org/html/rfc7523#section-2.2.
+const clientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// assertionLifetime is how long signed assertions are valid for.
+const assertionLifetime = 5 * time.Minute
+
+// LoadPrivateKey reads an RSA or EC private key from a PEM file. PKCS #1,
+// PKCS #8 and SEC 1 encodings are supported.
+func LoadPrivateKey(filename string) (crypto.Signer, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", filename)
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse private key in %s: %v", filename, err)
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return k, nil
+	case *ecdsa.PrivateKey:
+		return k, nil
+	}
+
+	return nil, fmt.Errorf("unsupported private key type %T in %s", key, filename)
+}
+
+// signJWT creates a JWT with the given claims, signed with `RS256` for RSA
+// keys or `ES256`, `ES384` and `ES512` for EC keys depending on the curve.
+func signJWT(key crypto.Signer, keyID string, claims map[string]interface{}) (string, error) {
+	var alg string
+	var hash crypto.Hash
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		alg, hash = "RS256", crypto.SHA256
+	case *ecdsa.PrivateKey:
+		switch k.Curve {
+		case elliptic.P256():
+			alg, hash = "ES256", crypto.SHA256
+		case elliptic.P384():
+			alg, hash = "ES384", crypto.SHA384
+		case elliptic.P521():
+			alg, hash = "ES512", crypto.SHA512
+		default:
+			return "", errors.New("unsupported elliptic curve")
+		}
+	default:
+		return "", fmt.Errorf("unsupported private key type %T", key)
+	}
+
+	header := map[string]interface{}{
+		"alg": alg,
+		"typ": "JWT",
+	}
+	if keyID != "" {
+		header["kid"] = keyID
+	}
+
+	parts := []string{}
+	for _, part := range []map[string]interface{}{header, claims} {
+		data, err := json.Marshal(part)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, base64.RawURLEncoding.EncodeToString(data))
+	}
+
+	signingInput := strings.Join(parts, ".")
+	h := hash.New()
+	h.Write([]byte(signingInput))
+	digest := h.Sum(nil)
+
+	var signature []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		sig, err := rsa.SignPKCS1v15(rand.Reader, k, hash, digest)
+		if err != nil {
+			return "", err
+		}
+		signature = sig
+	case *ecdsa.PrivateKey:
+		// JWS uses the fixed-size concatenation of R and S rather than ASN.1.
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest)
+		if err != nil {
+			return "", err
+		}
+		size := (k.Curve.Params().BitSize + 7) / 8
+		signature = make([]byte, 2*size)
+		rBytes, sBytes := r.Bytes(), s.Bytes()
+		copy(signature[size-len(rBytes):size], rBytes)
+		copy(signature[2*size-len(sBytes):], sBytes)
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// JWTAssertionTokenSource gets tokens by signing a JWT with a private key. By
+// default the JWT authenticates the client for a client credentials grant
+// (`private_key_jwt`), or set `GrantType` to `JWTBearerGrant` to use the JWT
+// itself as the authorization grant.
+type JWTAssertionTokenSource struct {
+	ClientID       string
+	TokenURL       string
+	GrantType      string
+	Key            crypto.Signer
+	KeyID          string
+	Subject        string
+	Audience       string
+	Claims         map[string]interface{}
+	EndpointParams *url.Values
+	Scopes         []string
+}
+
+// Token generates a new token using a signed JWT assertion.
+func (s *JWTAssertionTokenSource) Token() (*oauth2.Token, error) {
+	jti, err := randomString(16)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	claims := map[string]interface{}{
+		"iss": s.ClientID,
+		"sub": s.ClientID,
+		"aud": s.TokenURL,
+		"iat": now.Unix(),
+		"exp": now.Add(assertionLifetime).Unix(),
+		"jti": jti,
+	}
+
+	if s.Subject != "" {
+		claims["sub"] = s.Subject
+	}
+
+	if s.Audience != "" {
+		claims["aud"] = s.Audience
+	}
+
+	for k, v := range s.Claims {
+		claims[k] = v
+	}
+
+	assertion, err := signJWT(s.Key, s.KeyID, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := url.Values{}
+	if s.EndpointParams != nil {
+		for k, v := range *s.EndpointParams {
+			payload[k] = v
+		}
+	}
+
+	if s.GrantType == JWTBearerGrant {
+		payload.Set("grant_type", JWTBearerGrant)
+		payload.Set("assertion", assertion)
+	} else {
+		payload.Set("grant_type", "client_credentials")
+		payload.Set("client_id", s.ClientID)
+		payload.Set("client_assertion_type", clientAssertionType)
+		payload.Set("client_assertion", assertion)
+	}
+
+	if len(s.Scopes) > 0 {
+		payload.Set("scope", strings.Join(s.Scopes, " "))
+	}
+
+	return requestToken(s.TokenURL, payload.Encode())
+}
+
+// JWTHandler authenticates with a JWT signed by a private key, e.g. for
+// service accounts. The profile stores the `client_id` and the path to a PEM
+// `private_key_file`, and optionally a `key_id` and `subject`. If `Issuer` is
+// set and `TokenURL` is empty, then the token URL is discovered from the
+// issuer's OpenID Connect configuration.
+type JWTHandler struct {
+	Issuer   string
+	TokenURL string
+
+	// GrantType is empty to use `private_key_jwt` client authentication with
+	// the client credentials grant, or `JWTBearerGrant`.
+	GrantType string
+
+	// Audience of the JWT. Defaults to the token URL.
+	Audience string
+
+	// Claims are extra claims to include in the JWT.
+	Claims map[string]interface{}
+
+	Keys   []string
+	Params []string
+	Scopes []string
+}
+
+// ProfileKeys returns the key names for fields to store in the profile.
+func (h *JWTHandler) ProfileKeys() []string {
+	return append([]string{"client_id", "private_key_file"}, h.Keys...)
+}
+
+// ValidateProfile checks that a new profile has a client ID and a usable
+// private key.
+func (h *JWTHandler) ValidateProfile(profile map[string]string) error {
+	if profile["credential_process"] != "" {
+		// Values are only available at request time.
+		return nil
+	}
+
+	if profile["client_id"] == "" || profile["private_key_file"] == "" {
+		return ErrInvalidProfile
+	}
+
+	_, err := LoadPrivateKey(profile["private_key_file"])
+	return err
+}
+
+// OnRequest gets run before the request goes out on the wire.
+func (h *JWTHandler) OnRequest(log *zerolog.Logger, request *http.Request) error {
+	if request.Header.Get("Authorization") == "" {
+		// No auth is set, so let's get the token either from a cache
+		// or generate a new one from the issuing server.
+		profile, err := cli.LoadProfile()
+		if err != nil {
+			return err
+		}
+
+		if profile["client_id"] == "" || profile["private_key_file"] == "" {
+			return ErrInvalidProfile
+		}
+
+		tokenURL := h.TokenURL
+		if _, err := discoverEndpoints(h.Issuer, map[string]*string{
+			"token_endpoint": &tokenURL,
+		}); err != nil {
+			return err
+		}
+
+		key, err := LoadPrivateKey(profile["private_key_file"])
+		if err != nil {
+			return err
+		}
+
+		params := url.Values{}
+		for _, name := range h.Params {
+			params.Add(name, profile[name])
+		}
+
+		source := &JWTAssertionTokenSource{
+			ClientID:       profile["client_id"],
+			TokenURL:       tokenURL,
+			GrantType:      h.GrantType,
+			Key:            key,
+			KeyID:          profile["key_id"],
+			Subject:        profile["subject"],
+			Audience:       h.Audience,
+			Claims:         h.Claims,
+			EndpointParams: &params,
+			Scopes:         h.Scopes,
+		}
+
+		return TokenHandler(source, log, request)
+	}
+
+	return nil
+}
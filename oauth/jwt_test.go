@@ -0,0 +1,134 @@
+package oauth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// verifyJWT checks the signature of a JWT and returns its header and claims.
+func verifyJWT(t *testing.T, token string, public crypto.PublicKey) (map[string]interface{}, map[string]interface{}) {
+	parts := strings.Split(token, ".")
+	assert.Len(t, parts, 3)
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	assert.NoError(t, err)
+
+	switch k := public.(type) {
+	case *rsa.PublicKey:
+		assert.NoError(t, rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], signature))
+	case *ecdsa.PublicKey:
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		assert.True(t, ecdsa.Verify(k, digest[:], r, s))
+	}
+
+	decoded := []map[string]interface{}{}
+	for _, part := range parts[:2] {
+		data, err := base64.RawURLEncoding.DecodeString(part)
+		assert.NoError(t, err)
+
+		value := map[string]interface{}{}
+		assert.NoError(t, json.Unmarshal(data, &value))
+		decoded = append(decoded, value)
+	}
+
+	return decoded[0], decoded[1]
+}
+
+func TestJWTAssertionTokenSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jwt")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	ecBytes, err := x509.MarshalECPrivateKey(ecKey)
+	assert.NoError(t, err)
+
+	rsaFile := path.Join(dir, "rsa.pem")
+	assert.NoError(t, ioutil.WriteFile(rsaFile, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}), 0600))
+
+	ecFile := path.Join(dir, "ec.pem")
+	assert.NoError(t, ioutil.WriteFile(ecFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecBytes}), 0600))
+
+	var public crypto.PublicKey
+	var tokenURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+
+		assertion := r.PostForm.Get("client_assertion")
+		if r.PostForm.Get("grant_type") == JWTBearerGrant {
+			assertion = r.PostForm.Get("assertion")
+		} else {
+			assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+			assert.Equal(t, clientAssertionType, r.PostForm.Get("client_assertion_type"))
+		}
+
+		header, claims := verifyJWT(t, assertion, public)
+		assert.Equal(t, "key1", header["kid"])
+		assert.Equal(t, "svc1", claims["iss"])
+		assert.Equal(t, "tenant1", claims["tenant"])
+		assert.NotEmpty(t, claims["jti"])
+
+		if r.PostForm.Get("grant_type") == JWTBearerGrant {
+			assert.Equal(t, "user1", claims["sub"])
+			assert.Equal(t, "https://api.example.com", claims["aud"])
+		} else {
+			assert.Equal(t, "svc1", claims["sub"])
+			assert.Equal(t, tokenURL, claims["aud"])
+		}
+
+		w.Write([]byte(`{"access_token": "abc", "token_type": "Bearer", "expires_in": 3600}`))
+	}))
+	defer server.Close()
+	tokenURL = server.URL + "/token"
+
+	for _, filename := range []string{rsaFile, ecFile} {
+		key, err := LoadPrivateKey(filename)
+		assert.NoError(t, err)
+		public = key.Public()
+
+		source := &JWTAssertionTokenSource{
+			ClientID: "svc1",
+			TokenURL: tokenURL,
+			Key:      key,
+			KeyID:    "key1",
+			Claims:   map[string]interface{}{"tenant": "tenant1"},
+		}
+
+		token, err := source.Token()
+		assert.NoError(t, err)
+		assert.Equal(t, "abc", token.AccessToken)
+
+		source.GrantType = JWTBearerGrant
+		source.Subject = "user1"
+		source.Audience = "https://api.example.com"
+
+		token, err = source.Token()
+		assert.NoError(t, err)
+		assert.Equal(t, "abc", token.AccessToken)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/danielgtaylor/openapi-cli-generator/cli"
 	"github.com/rs/zerolog"
@@ -15,9 +16,11 @@ import (
 )
 
 type config struct {
-	getParams func(profile map[string]string) url.Values
-	extra     []string
-	scopes    []string
+	getParams    func(profile map[string]string) url.Values
+	extra        []string
+	scopes       []string
+	redirectPort int
+	loginTimeout time.Duration
 }
 
 // ErrInvalidProfile is thrown when a profile is missing or invalid.
@@ -49,6 +52,23 @@ func Scopes(scopes ...string) func(*config) error {
 	}
 }
 
+// RedirectPort sets the loopback port to receive the login redirect on. Use
+// `RandomPort` to pick any free port.
+func RedirectPort(port int) func(*config) error {
+	return func(c *config) error {
+		c.redirectPort = port
+		return nil
+	}
+}
+
+// LoginTimeout sets how long to wait for the user to log in.
+func LoginTimeout(timeout time.Duration) func(*config) error {
+	return func(c *config) error {
+		c.loginTimeout = timeout
+		return nil
+	}
+}
+
 // TokenMiddleware is a wrapper around TokenHandler.
 func TokenMiddleware(source oauth2.TokenSource, ctx *context.Context, h context.Handler) {
 	// Setup logger with the current profile.
@@ -65,59 +85,164 @@ func TokenMiddleware(source oauth2.TokenSource, ctx *context.Context, h context.
 // add the token auth as a header. Uses the CLI cache to store tokens on a per-
 // profile basis between runs.
 func TokenHandler(source oauth2.TokenSource, log *zerolog.Logger, request *http.Request) error {
-	var cached *oauth2.Token
-
 	// Load any existing token from the CLI's cache file.
-	expiresKey := "profiles." + viper.GetString("profile") + ".expires"
-	typeKey := "profiles." + viper.GetString("profile") + ".type"
-	tokenKey := "profiles." + viper.GetString("profile") + ".token"
-	refreshKey := "profiles." + viper.GetString("profile") + ".refresh"
+	prefix := "profiles." + viper.GetString("profile") + "."
+	token := cachedToken(prefix)
 
-	expiry := cli.Cache.GetTime(expiresKey)
-	if !expiry.IsZero() {
+	if token.Valid() {
 		log.Debug().Msg("Loading token from cache.")
-		cached = &oauth2.Token{
-			AccessToken:  cli.Cache.GetString(tokenKey),
-			RefreshToken: cli.Cache.GetString(refreshKey),
-			TokenType:    cli.Cache.GetString(typeKey),
-			Expiry:       expiry,
+	} else {
+		var err error
+		if token, err = lockedToken(source, log, prefix); err != nil {
+			return err
 		}
 	}
 
-	if cached != nil {
-		// Wrap the token source preloaded with our cached token.
-		source = oauth2.ReuseTokenSource(cached, source)
+	if token == nil {
+		// An interactive login may take much longer than other processes wait
+		// for the lock, so it is done without holding it.
+		if refresher, ok := source.(RefreshTokenSource); ok {
+			// The refresh token was already tried.
+			source = refresher.TokenSource
+		}
+
+		var err error
+		if token, err = source.Token(); err != nil {
+			return err
+		}
+
+		if err := storeToken(log, prefix, token); err != nil {
+			return err
+		}
 	}
 
-	// Get the next available token from the source.
-	token, err := source.Token()
+	// Set the auth header so the request can be made.
+	token.SetAuthHeader(request)
+	return nil
+}
+
+// lockedToken gets a new token while holding the profile's token lock. Only
+// one process at a time may do this, since refresh tokens may only be valid
+// for a single use. Once the lock is held, the cache is checked again in case
+// another process has already refreshed the token. Returns nil if a token can
+// only be obtained interactively.
+func lockedToken(source oauth2.TokenSource, log *zerolog.Logger, prefix string) (*oauth2.Token, error) {
+	unlock, err := cli.Lock("token-" + viper.GetString("profile"))
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer unlock()
 
-	if cached == nil || (token.AccessToken != cached.AccessToken) {
-		// Token either didn't exist in the cache or has changed, so let's write
-		// the new values to the CLI cache.
-		log.Debug().Msg("Token refreshed. Updating cache.")
+	cli.ReloadCache()
+	cached := cachedToken(prefix)
+	if cached.Valid() {
+		log.Debug().Msg("Loading token refreshed by another process from cache.")
+		return cached, nil
+	}
 
-		cli.Cache.Set(expiresKey, token.Expiry)
-		cli.Cache.Set(typeKey, token.Type())
-		cli.Cache.Set(tokenKey, token.AccessToken)
+	var token *oauth2.Token
+	if !isInteractive(source) {
+		if token, err = source.Token(); err != nil {
+			return nil, err
+		}
+	} else if refresher, ok := source.(RefreshTokenSource); ok && cached != nil && cached.RefreshToken != "" {
+		if token, err = refresher.refresh(cached.RefreshToken); err != nil {
+			log.Debug().Err(err).Msg("Could not refresh token")
+			return nil, nil
+		}
+	}
+
+	if token == nil {
+		return nil, nil
+	}
+
+	return token, storeToken(log, prefix, token)
+}
+
+// isInteractive returns whether getting a token from the source may need the
+// user to log in.
+func isInteractive(source oauth2.TokenSource) bool {
+	switch s := source.(type) {
+	case RefreshTokenSource:
+		return isInteractive(s.TokenSource)
+	case *AuthorizationCodeTokenSource, *DeviceCodeTokenSource:
+		return true
+	}
+
+	return false
+}
+
+// storeToken writes a new token to the CLI cache.
+func storeToken(log *zerolog.Logger, prefix string, token *oauth2.Token) error {
+	log.Debug().Msg("Token refreshed. Updating cache.")
+
+	return cli.UpdateCache(func(cache *viper.Viper) {
+		cache.Set(prefix+"expires", token.Expiry)
+		cache.Set(prefix+"type", token.Type())
+		cache.Set(prefix+"token", token.AccessToken)
 
 		if token.RefreshToken != "" {
 			// Only set the refresh token if present. This prevents overwriting it
 			// after using a refresh token, because the newly returned token won't
 			// have another refresh token set on it (you keep using the same one).
-			cli.Cache.Set(refreshKey, token.RefreshToken)
+			cache.Set(prefix+"refresh", token.RefreshToken)
 		}
+	})
+}
 
-		// Save the cache to disk.
-		if err := cli.Cache.WriteConfig(); err != nil {
-			return err
-		}
+// cachedToken loads a token from the CLI's cache using the given key prefix,
+// or returns nil if no token is cached.
+func cachedToken(prefix string) *oauth2.Token {
+	expiry := cli.Cache.GetTime(prefix + "expires")
+	if expiry.IsZero() {
+		return nil
 	}
 
-	// Set the auth header so the request can be made.
-	token.SetAuthHeader(request)
-	return nil
+	return &oauth2.Token{
+		AccessToken:  cli.Cache.GetString(prefix + "token"),
+		RefreshToken: cli.Cache.GetString(prefix + "refresh"),
+		TokenType:    cli.Cache.GetString(prefix + "type"),
+		Expiry:       expiry,
+	}
+}
+
+// refreshOnUnauthorized expires the current profile's cached access token if
+// the server rejected it, so that the request is retried with a new token,
+// e.g. fetched using the refresh token.
+func refreshOnUnauthorized(log *zerolog.Logger, response *http.Response) (bool, error) {
+	if response.StatusCode != http.StatusUnauthorized {
+		return false, nil
+	}
+
+	log.Info().Msg("Access token was rejected, fetching a new one")
+
+	prefix := "profiles." + viper.GetString("profile") + "."
+	return true, cli.UpdateCache(func(cache *viper.Viper) {
+		cache.Set(prefix+"token", "")
+		cache.Set(prefix+"expires", time.Time{})
+	})
+}
+
+// OnResponse fetches a new token and retries the request if the cached
+// access token was rejected.
+func (h *AuthCodeHandler) OnResponse(log *zerolog.Logger, request *http.Request, response *http.Response) (bool, error) {
+	return refreshOnUnauthorized(log, response)
+}
+
+// OnResponse fetches a new token and retries the request if the cached
+// access token was rejected.
+func (h *DeviceCodeHandler) OnResponse(log *zerolog.Logger, request *http.Request, response *http.Response) (bool, error) {
+	return refreshOnUnauthorized(log, response)
+}
+
+// OnResponse fetches a new token and retries the request if the cached
+// access token was rejected.
+func (h *ClientCredentialsHandler) OnResponse(log *zerolog.Logger, request *http.Request, response *http.Response) (bool, error) {
+	return refreshOnUnauthorized(log, response)
+}
+
+// OnResponse fetches a new token and retries the request if the cached
+// access token was rejected.
+func (h *JWTHandler) OnResponse(log *zerolog.Logger, request *http.Request, response *http.Response) (bool, error) {
+	return refreshOnUnauthorized(log, response)
 }
@@ -0,0 +1,100 @@
+package oauth
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/openapi-cli-generator/cli"
+	"github.com/danielgtaylor/openapi-cli-generator/cli/clitest"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenHandlerLocking(t *testing.T) {
+	clitest.Setup(t)
+	viper.Set("profile", "default")
+
+	refreshes := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.PostForm.Get("grant_type") == "refresh_token" {
+			refreshes++
+			if r.PostForm.Get("refresh_token") != "refresh1" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error": "invalid_grant"}`))
+				return
+			}
+			w.Write([]byte(`{"access_token": "refreshed", "token_type": "Bearer", "expires_in": 3600}`))
+			return
+		}
+		w.Write([]byte(`{"access_token": "login", "token_type": "Bearer", "expires_in": 3600}`))
+	}))
+	defer server.Close()
+
+	source := &AuthorizationCodeTokenSource{
+		ClientID:     "id1",
+		AuthorizeURL: server.URL + "/authorize",
+		TokenURL:     server.URL + "/token",
+		RedirectPort: RandomPort,
+		LoginTimeout: 5 * time.Second,
+		NoBrowser:    true,
+	}
+
+	expire := func(refresh string) {
+		assert.NoError(t, cli.UpdateCache(func(cache *viper.Viper) {
+			cache.Set("profiles.default.token", "expired")
+			cache.Set("profiles.default.expires", time.Now().Add(-time.Hour))
+			cache.Set("profiles.default.refresh", refresh)
+		}))
+	}
+
+	log := zerolog.Nop()
+	handle := func() string {
+		req, _ := http.NewRequest(http.MethodGet, "https://example.com/", nil)
+		assert.NoError(t, TokenHandler(RefreshTokenSource{
+			ClientID:     "id1",
+			TokenURL:     server.URL + "/token",
+			RefreshToken: cli.Cache.GetString("profiles.default.refresh"),
+			TokenSource:  source,
+		}, &log, req))
+		return req.Header.Get("Authorization")
+	}
+
+	// A valid refresh token is used without logging in.
+	expire("refresh1")
+	assert.Equal(t, "Bearer refreshed", handle())
+	assert.Equal(t, 1, refreshes)
+
+	// Otherwise the user logs in, without holding the token lock so that
+	// other processes don't time out waiting for it.
+	expire("revoked")
+	r, w := io.Pipe()
+	cli.Stderr = w
+	go func() {
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			if strings.HasPrefix(scanner.Text(), "http") {
+				authURL, err := url.Parse(scanner.Text())
+				assert.NoError(t, err)
+
+				unlock, err := cli.Lock("token-default")
+				assert.NoError(t, err)
+				unlock()
+
+				http.Get(authURL.Query().Get("redirect_uri") + "?code=code1&state=" + authURL.Query().Get("state"))
+			}
+		}
+	}()
+
+	token := handle()
+	w.Close()
+	assert.Equal(t, "Bearer login", token)
+	assert.Equal(t, 2, refreshes)
+}
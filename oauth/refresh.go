@@ -34,10 +34,7 @@ type RefreshTokenSource struct {
 // back to the original source.
 func (ts RefreshTokenSource) Token() (*oauth2.Token, error) {
 	if ts.RefreshToken != "" {
-		log.Debug().Msg("Trying refresh token to get a new access token")
-		payload := fmt.Sprintf("grant_type=refresh_token&client_id=%s&refresh_token=%s", ts.ClientID, ts.RefreshToken)
-
-		token, err := requestToken(ts.TokenURL, payload)
+		token, err := ts.refresh(ts.RefreshToken)
 		if err == nil {
 			return token, err
 		}
@@ -55,3 +52,11 @@ func (ts RefreshTokenSource) Token() (*oauth2.Token, error) {
 
 	return token, nil
 }
+
+// refresh gets a new token using the given refresh token.
+func (ts RefreshTokenSource) refresh(refreshToken string) (*oauth2.Token, error) {
+	log.Debug().Msg("Trying refresh token to get a new access token")
+	payload := fmt.Sprintf("grant_type=refresh_token&client_id=%s&refresh_token=%s", ts.ClientID, refreshToken)
+
+	return requestToken(ts.TokenURL, payload)
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/danielgtaylor/openapi-cli-generator/cli"
 	"github.com/rs/zerolog/log"
 	"golang.org/x/oauth2"
 )
@@ -23,6 +24,18 @@ type tokenResponse struct {
 	Expiry       time.Time     `json:"expiry,omitempty"`
 }
 
+// tokenError is returned when the token endpoint responds with an error. The
+// code is the standard OAuth 2.0 `error` value, if one could be parsed.
+type tokenError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+	body        []byte
+}
+
+func (e *tokenError) Error() string {
+	return fmt.Sprintf("bad response from token endpoint:\n%s", e.body)
+}
+
 // requestToken from the given URL with the given payload. This can be used
 // for many different grant types and will return a parsed token.
 func requestToken(tokenURL, payload string) (*oauth2.Token, error) {
@@ -34,7 +47,12 @@ func requestToken(tokenURL, payload string) (*oauth2.Token, error) {
 
 	req.Header.Add("content-type", "application/x-www-form-urlencoded")
 
-	res, err := http.DefaultClient.Do(req)
+	client, err := cli.HTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -45,7 +63,9 @@ func requestToken(tokenURL, payload string) (*oauth2.Token, error) {
 	log.Debug().Str("url", tokenURL).Bytes("body", body).Msg("Got response")
 
 	if res.StatusCode > 200 {
-		return nil, fmt.Errorf("bad response from token endpoint:\n%s", body)
+		tokenErr := &tokenError{body: body}
+		json.Unmarshal(body, tokenErr)
+		return nil, tokenErr
 	}
 
 	decoded := tokenResponse{}
@@ -0,0 +1,115 @@
+package oauth
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/danielgtaylor/openapi-cli-generator/cli"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+)
+
+// RevokeToken revokes an access or refresh token as described in
+// https://tools.ietf.org/html/rfc7009. The hint is either `access_token` or
+// `refresh_token`. The client secret is only needed for confidential clients.
+func RevokeToken(revocationURL, clientID, clientSecret, token, hint string) error {
+	params := url.Values{}
+	params.Set("token", token)
+	params.Set("token_type_hint", hint)
+	params.Set("client_id", clientID)
+	if clientSecret != "" {
+		params.Set("client_secret", clientSecret)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, revocationURL, strings.NewReader(params.Encode()))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Add("content-type", "application/x-www-form-urlencoded")
+
+	client, err := cli.HTTPClient()
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+	body, _ := ioutil.ReadAll(res.Body)
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("bad response from revocation endpoint:\n%s", body)
+	}
+
+	return nil
+}
+
+// revokeCachedTokens revokes the current profile's cached tokens. The
+// revocation URL is discovered from the issuer if not set. Nothing is done if
+// there is no revocation endpoint.
+func revokeCachedTokens(log *zerolog.Logger, issuer, revocationURL, clientID, clientSecret string) error {
+	if revocationURL == "" && issuer != "" {
+		metadata, err := Discover(issuer)
+		if err != nil {
+			return err
+		}
+		revocationURL = metadata.RevocationEndpoint
+	}
+
+	if revocationURL == "" {
+		return nil
+	}
+
+	prefix := "profiles." + viper.GetString("profile") + "."
+
+	// Revoke the refresh token first, since many providers then also revoke
+	// the access tokens issued with it.
+	for _, key := range []string{"refresh", "token"} {
+		token := cli.Cache.GetString(prefix + key)
+		if token == "" {
+			continue
+		}
+
+		hint := "access_token"
+		if key == "refresh" {
+			hint = "refresh_token"
+		}
+
+		log.Debug().Str("url", revocationURL).Msgf("Revoking %s", hint)
+		if err := RevokeToken(revocationURL, clientID, clientSecret, token, hint); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Logout revokes the current profile's cached tokens if the provider has a
+// revocation endpoint.
+func (h *AuthCodeHandler) Logout(log *zerolog.Logger) error {
+	return revokeCachedTokens(log, h.Issuer, h.RevocationURL, h.ClientID, "")
+}
+
+// Logout revokes the current profile's cached tokens if the provider has a
+// revocation endpoint.
+func (h *DeviceCodeHandler) Logout(log *zerolog.Logger) error {
+	return revokeCachedTokens(log, h.Issuer, h.RevocationURL, h.ClientID, "")
+}
+
+// Logout revokes the current profile's cached tokens if the provider has a
+// revocation endpoint.
+func (h *ClientCredentialsHandler) Logout(log *zerolog.Logger) error {
+	profile, err := cli.LoadProfile()
+	if err != nil {
+		return err
+	}
+
+	return revokeCachedTokens(log, h.Issuer, h.RevocationURL, profile["client_id"], profile["client_secret"])
+}
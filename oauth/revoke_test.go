@@ -0,0 +1,39 @@
+package oauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielgtaylor/openapi-cli-generator/cli"
+	"github.com/danielgtaylor/openapi-cli-generator/cli/clitest"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogoutRevokesTokens(t *testing.T) {
+	clitest.Setup(t)
+	viper.Set("profile", "default")
+	cli.Cache.Set("profiles.default.token", "access1")
+	cli.Cache.Set("profiles.default.refresh", "refresh1")
+
+	revoked := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		assert.Equal(t, "id1", r.PostForm.Get("client_id"))
+		revoked = append(revoked, r.PostForm.Get("token_type_hint")+":"+r.PostForm.Get("token"))
+	}))
+	defer server.Close()
+
+	log := zerolog.Nop()
+
+	// Without a revocation endpoint there is nothing to do.
+	handler := &AuthCodeHandler{ClientID: "id1"}
+	assert.NoError(t, handler.Logout(&log))
+	assert.Empty(t, revoked)
+
+	handler.RevocationURL = server.URL
+	assert.NoError(t, handler.Logout(&log))
+	assert.Equal(t, []string{"refresh_token:refresh1", "access_token:access1"}, revoked)
+}
@@ -0,0 +1,256 @@
+// Package signing provides authentication profile support for APIs that
+// require each request to be signed, like gateways using an HMAC of the
+// request or AWS services using Signature Version 4.
+package signing
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/danielgtaylor/openapi-cli-generator/cli"
+	"github.com/rs/zerolog"
+)
+
+// now returns the current time and is only replaced by tests.
+var now = time.Now
+
+// readBody returns the request body and replaces it so it can be sent.
+func readBody(request *http.Request) ([]byte, error) {
+	if request.Body == nil {
+		return []byte{}, nil
+	}
+
+	body, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		return nil, err
+	}
+	request.Body.Close()
+
+	request.Body = ioutil.NopCloser(bytes.NewReader(body))
+	request.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(body)), nil
+	}
+
+	return body, nil
+}
+
+// uriEncode percent-encodes everything except unreserved characters as
+// described in https://tools.ietf.org/html/rfc3986#section-2.3, optionally
+// keeping slashes.
+func uriEncode(value string, keepSlash bool) string {
+	sb := strings.Builder{}
+	for _, b := range []byte(value) {
+		switch {
+		case (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') || (b >= '0' && b <= '9'),
+			b == '-', b == '_', b == '.', b == '~', keepSlash && b == '/':
+			sb.WriteByte(b)
+		default:
+			fmt.Fprintf(&sb, "%%%02X", b)
+		}
+	}
+
+	return sb.String()
+}
+
+// canonicalQuery returns the encoded query parameters sorted by name and then
+// value.
+func canonicalQuery(query url.Values) string {
+	params := [][2]string{}
+	for name, values := range query {
+		for _, value := range values {
+			params = append(params, [2]string{uriEncode(name, false), uriEncode(value, false)})
+		}
+	}
+
+	// Sort the pairs rather than the joined strings, since e.g. `limit2=`
+	// would otherwise come before `limit=`.
+	sort.Slice(params, func(i, j int) bool {
+		if params[i][0] != params[j][0] {
+			return params[i][0] < params[j][0]
+		}
+		return params[i][1] < params[j][1]
+	})
+
+	joined := make([]string, len(params))
+	for i, param := range params {
+		joined[i] = param[0] + "=" + param[1]
+	}
+
+	return strings.Join(joined, "&")
+}
+
+// hashes are the supported HMAC hash functions by name.
+var hashes = map[string]func() hash.Hash{
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// HMACHandler signs requests with an HMAC using the profile's `key_id` and
+// `secret`. The string to sign is made of newline-separated values:
+//
+//	METHOD
+//	/path
+//	canonical query string
+//	name:value for each signed header
+//	timestamp
+//	hex-encoded hash of the body
+type HMACHandler struct {
+	// Algorithm is the hash function, one of `sha1`, `sha256` or `sha512`.
+	// Defaults to `sha256`.
+	Algorithm string
+
+	// Header is the header to put the signature in. Defaults to
+	// `Authorization`.
+	Header string
+
+	// Format is a Go template for the header value, which is passed the
+	// `KeyID`, `Signature`, `Timestamp` and `SignedHeaders`. Defaults to
+	// `HMAC {{ .KeyID }}:{{ .Signature }}`.
+	Format string
+
+	// TimestampHeader is the header to send the RFC 3339 timestamp in.
+	// Defaults to `X-Timestamp`.
+	TimestampHeader string
+
+	// SignedHeaders are the names of extra headers to include in the
+	// signature.
+	SignedHeaders []string
+
+	// HexEncoding encodes the signature as hex instead of base64.
+	HexEncoding bool
+
+	Keys []string
+}
+
+// ProfileKeys returns the key names for fields to store in the profile.
+func (h *HMACHandler) ProfileKeys() []string {
+	return append([]string{"key_id", "secret"}, h.Keys...)
+}
+
+// OnRequest gets run before the request goes out on the wire. Requests are
+// signed later in `Sign` once the body is final.
+func (h *HMACHandler) OnRequest(log *zerolog.Logger, request *http.Request) error {
+	return nil
+}
+
+// Sign adds the timestamp and signature headers to the request.
+func (h *HMACHandler) Sign(log *zerolog.Logger, request *http.Request) error {
+	profile, err := cli.LoadProfile()
+	if err != nil {
+		return err
+	}
+
+	algorithm := h.Algorithm
+	if algorithm == "" {
+		algorithm = "sha256"
+	}
+
+	hashFunc := hashes[strings.ToLower(algorithm)]
+	if hashFunc == nil {
+		return fmt.Errorf("unsupported HMAC algorithm %s", algorithm)
+	}
+
+	header := h.Header
+	if header == "" {
+		header = "Authorization"
+	}
+
+	format := h.Format
+	if format == "" {
+		format = "HMAC {{ .KeyID }}:{{ .Signature }}"
+	}
+
+	timestampHeader := h.TimestampHeader
+	if timestampHeader == "" {
+		timestampHeader = "X-Timestamp"
+	}
+
+	tmpl, err := template.New("signature").Parse(format)
+	if err != nil {
+		return err
+	}
+
+	body, err := readBody(request)
+	if err != nil {
+		return err
+	}
+
+	timestamp := now().UTC().Format(time.RFC3339)
+	request.Header.Set(timestampHeader, timestamp)
+
+	bodyHash := hashFunc()
+	bodyHash.Write(body)
+
+	path := request.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	lines := []string{
+		request.Method,
+		path,
+		canonicalQuery(request.URL.Query()),
+	}
+
+	names := []string{}
+	for _, name := range h.SignedHeaders {
+		name = strings.ToLower(name)
+		names = append(names, name)
+
+		value := request.Header.Get(name)
+		if name == "host" {
+			value = request.Host
+			if value == "" {
+				value = request.URL.Host
+			}
+		}
+
+		lines = append(lines, name+":"+strings.TrimSpace(value))
+	}
+
+	lines = append(lines, timestamp, hex.EncodeToString(bodyHash.Sum(nil)))
+
+	mac := hmac.New(hashFunc, []byte(profile["secret"]))
+	mac.Write([]byte(strings.Join(lines, "\n")))
+
+	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	if h.HexEncoding {
+		signature = hex.EncodeToString(mac.Sum(nil))
+	}
+
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, map[string]string{
+		"KeyID":         profile["key_id"],
+		"Signature":     signature,
+		"Timestamp":     timestamp,
+		"SignedHeaders": strings.Join(names, ";"),
+	}); err != nil {
+		return err
+	}
+
+	request.Header.Set(header, buf.String())
+
+	return nil
+}
+
+// InitHMAC sets up HMAC request signing. Must be called *after* you have
+// called `cli.Init()`.
+func InitHMAC(handler *HMACHandler) {
+	cli.UseAuth("", handler)
+}
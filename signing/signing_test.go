@@ -0,0 +1,96 @@
+package signing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/openapi-cli-generator/cli"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHMACSignsFinalRequest(t *testing.T) {
+	cli.Init(&cli.Config{
+		AppName:   "test",
+		EnvPrefix: "TEST",
+	})
+	InitHMAC(&HMACHandler{
+		Format:        "HMAC {{ .KeyID }}:{{ .SignedHeaders }}:{{ .Signature }}",
+		SignedHeaders: []string{"Content-Type"},
+		HexEncoding:   true,
+	})
+	cli.Creds.Set("profiles.default.key_id", "key1")
+	cli.Creds.Set("profiles.default.secret", "secret1")
+
+	now = func() time.Time { return time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodyHash := sha256.Sum256(body)
+
+		mac := hmac.New(sha256.New, []byte("secret1"))
+		mac.Write([]byte(strings.Join([]string{
+			"POST",
+			"/items",
+			"a=1&b=x%20y",
+			"content-type:application/json",
+			"2020-01-02T03:04:05Z",
+			hex.EncodeToString(bodyHash[:]),
+		}, "\n")))
+
+		assert.JSONEq(t, `{"hello":"world"}`, string(body))
+		assert.Equal(t, "2020-01-02T03:04:05Z", r.Header.Get("X-Timestamp"))
+		assert.Equal(t, "HMAC key1:content-type:"+hex.EncodeToString(mac.Sum(nil)), r.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+
+	// The body and query are set on the request, after the client's request
+	// middleware has run, so they must still be included in the signature.
+	res, err := cli.Client.Post().URL(server.URL+"/items").
+		AddQuery("b", "x y").AddQuery("a", "1").
+		JSON(map[string]string{"hello": "world"}).Do()
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+}
+
+func TestAWSSignature(t *testing.T) {
+	cli.Init(&cli.Config{
+		AppName:   "test",
+		EnvPrefix: "TEST",
+	})
+	InitAWS("service", "us-east-1")
+	cli.Creds.Set("profiles.default.access_key_id", "AKIDEXAMPLE")
+	cli.Creds.Set("profiles.default.secret_access_key", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY")
+
+	now = func() time.Time { return time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	// The `get-vanilla` case from the AWS Signature Version 4 test suite.
+	req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	assert.NoError(t, err)
+
+	log := zerolog.Nop()
+	assert.NoError(t, cli.AuthHandlers[""].(cli.AuthSigner).Sign(&log, req))
+	assert.Equal(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+	assert.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31", req.Header.Get("Authorization"))
+}
+
+func TestCanonicalQuery(t *testing.T) {
+	query := url.Values{
+		"limit2":      {"2"},
+		"limit":       {"1"},
+		"filter.name": {"b c"},
+		"filter":      {"z", "a"},
+	}
+
+	assert.Equal(t, "filter=a&filter=z&filter.name=b%20c&limit=1&limit2=2", canonicalQuery(query))
+}